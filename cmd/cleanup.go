@@ -0,0 +1,66 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nuxencs/seasonpackarr/internal/buildinfo"
+	"github.com/nuxencs/seasonpackarr/internal/cleanup"
+	"github.com/nuxencs/seasonpackarr/internal/config"
+	"github.com/nuxencs/seasonpackarr/internal/database"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// cleanupCmd represents the cleanup command
+var cleanupCmd = &cobra.Command{
+	Use:     "cleanup",
+	Short:   "Remove orphaned pack folders from the pre import paths",
+	Example: `  seasonpackarr cleanup --config "/path/to/config" --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New(configPath, buildinfo.Version)
+		log := logger.New(cfg.Config)
+
+		db, err := database.NewDB(cfg.Config, log)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		if err := db.Open(); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		defer db.Close()
+
+		results, err := cleanup.New(log, cfg, database.NewMatchRepo(log, db), database.NewHistoryRepo(log, db)).
+			Run(context.Background(), dryRun)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No orphaned pack folders found")
+			return
+		}
+
+		for _, res := range results {
+			fmt.Println(res.Dir)
+			for _, path := range res.Removed {
+				if dryRun {
+					fmt.Printf("  would remove: %s\n", path)
+				} else {
+					fmt.Printf("  removed: %s\n", path)
+				}
+			}
+			for _, path := range res.Kept {
+				fmt.Printf("  kept, not a link: %s\n", path)
+			}
+		}
+	},
+}
@@ -30,7 +30,7 @@ var packCmd = &cobra.Command{
 			return
 		}
 
-		err = payload.ExecRequest(fmt.Sprintf("http://%s:%d/api/pack", host, port), body, apiKey)
+		err = payload.ExecRequest(fmt.Sprintf("http://%s:%d/api/pack?dryRun=%t", host, port, dryRun), body, apiKey)
 		if err != nil {
 			fmt.Println(err.Error())
 			return
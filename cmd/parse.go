@@ -68,7 +68,7 @@ var parseCmd = &cobra.Command{
 			return
 		}
 
-		err = payload.ExecRequest(fmt.Sprintf("http://%s:%d/api/parse", host, port), body, apiKey)
+		err = payload.ExecRequest(fmt.Sprintf("http://%s:%d/api/parse?dryRun=%t", host, port, dryRun), body, apiKey)
 		if err != nil {
 			fmt.Println(err.Error())
 			return
@@ -17,6 +17,7 @@ var (
 	host        string
 	port        int
 	apiKey      string
+	dryRun      bool
 )
 
 var rootCmd = &cobra.Command{
@@ -36,12 +37,16 @@ For more information and examples, visit https://github.com/nuxencs/seasonpackar
 func init() {
 	startCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to the configuration directory")
 
+	cleanupCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to the configuration directory")
+	cleanupCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "only report what would be removed")
+
 	testCmd.PersistentFlags().StringVarP(&clientName, "client", "n", "", "name of the client you want to test")
 	testCmd.PersistentFlags().StringVarP(&host, "host", "i", "127.0.0.1", "host used by seasonpackarr")
 	testCmd.PersistentFlags().IntVarP(&port, "port", "p", 42069, "port used by seasonpackarr")
 	testCmd.PersistentFlags().StringVarP(&apiKey, "api", "a", "", "api key used by seasonpackarr")
+	testCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "d", false, "only report what would be hardlinked")
 
-	rootCmd.AddCommand(genTokenCmd, startCmd, testCmd, versionCmd)
+	rootCmd.AddCommand(cleanupCmd, genTokenCmd, startCmd, testCmd, versionCmd)
 	testCmd.AddCommand(packCmd, parseCmd)
 }
 
@@ -10,7 +10,9 @@ import (
 	"syscall"
 
 	"github.com/nuxencs/seasonpackarr/internal/buildinfo"
+	"github.com/nuxencs/seasonpackarr/internal/cleanup"
 	"github.com/nuxencs/seasonpackarr/internal/config"
+	"github.com/nuxencs/seasonpackarr/internal/database"
 	"github.com/nuxencs/seasonpackarr/internal/http"
 	"github.com/nuxencs/seasonpackarr/internal/logger"
 	"github.com/nuxencs/seasonpackarr/internal/notification"
@@ -40,7 +42,27 @@ var startCmd = &cobra.Command{
 		// init notification sender
 		noti := notification.NewDiscordSender(log, cfg)
 
-		srv := http.NewServer(log, cfg, noti)
+		// open database connection
+		db, err := database.NewDB(cfg.Config, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create new db")
+		}
+
+		if err := db.Open(); err != nil {
+			log.Fatal().Err(err).Msg("could not open db connection")
+		}
+
+		matchRepo := database.NewMatchRepo(log, db)
+		historyRepo := database.NewHistoryRepo(log, db)
+
+		srv := http.NewServer(log, cfg, noti, matchRepo, historyRepo)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		if interval := cfg.Config.Cleanup.Interval; interval > 0 {
+			go cleanup.New(log, cfg, matchRepo, historyRepo).Start(ctx, interval)
+		}
 
 		log.Info().Msgf("Starting seasonpackarr")
 		log.Info().Msgf("Version: %s", buildinfo.Version)
@@ -64,16 +86,26 @@ var startCmd = &cobra.Command{
 		select {
 		case sig := <-sigCh:
 			log.Info().Msgf("received signal: %q, shutting down server.", sig.String())
+			if err := db.Close(); err != nil {
+				log.Error().Err(err).Msg("error closing database")
+			}
 			os.Exit(0)
 
 		case err := <-errorChannel:
 			log.Error().Err(err).Msg("unexpected error from server")
 		}
+		cancel()
+
 		if err := srv.Shutdown(context.Background()); err != nil {
 			log.Error().Err(err).Msg("error during http shutdown")
 			os.Exit(1)
 		}
 
+		if err := db.Close(); err != nil {
+			log.Error().Err(err).Msg("error closing database")
+			os.Exit(1)
+		}
+
 		os.Exit(0)
 	},
 }
@@ -0,0 +1,304 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cleanup
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/clients"
+	"github.com/nuxencs/seasonpackarr/internal/config"
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// Cleaner removes pack folders from the pre import paths that no torrent in the clients points at,
+// e.g. because autobrr lost the race for the season pack or a filter rejected it later on. Only the pack
+// folders seasonpackarr created are touched, other folders in the pre import paths are left alone.
+type Cleaner struct {
+	log         zerolog.Logger
+	cfg         *config.AppConfig
+	matchRepo   domain.MatchRepo
+	historyRepo domain.HistoryRepo
+
+	// newClient is replaced in tests
+	newClient func(clientName string) (clients.TorrentClient, error)
+}
+
+// Result describes a pack folder that was cleaned up. Kept lists the files that weren't removed because
+// they aren't links, e.g. the only copy of an episode.
+type Result struct {
+	Dir     string
+	Removed []string
+	Kept    []string
+}
+
+func New(log logger.Logger, cfg *config.AppConfig, matchRepo domain.MatchRepo, historyRepo domain.HistoryRepo) *Cleaner {
+	c := &Cleaner{
+		log:         log.With().Str("module", "cleanup").Logger(),
+		cfg:         cfg,
+		matchRepo:   matchRepo,
+		historyRepo: historyRepo,
+	}
+
+	c.newClient = func(clientName string) (clients.TorrentClient, error) {
+		return clients.New(cfg.Config.Clients[clientName], cfg.Config.VideoExtensions, cfg.Config.SidecarExtensions)
+	}
+
+	return c
+}
+
+// Start runs the cleanup every interval until ctx is cancelled.
+func (c *Cleaner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.Run(ctx, false); err != nil {
+				c.log.Error().Err(err).Msg("error cleaning up pre import paths")
+			}
+		}
+	}
+}
+
+// Run cleans up the pre import paths of all clients. A pre import path is skipped entirely if the
+// torrents of any client using it can't be fetched, since its folders could still be in use.
+func (c *Cleaner) Run(ctx context.Context, dryRun bool) ([]Result, error) {
+	// clients can share a pre import path, so the torrents of all of them need to be checked
+	clientsByPath := make(map[string][]string)
+	for clientName, client := range c.cfg.Config.Clients {
+		preImportPath := filepath.Clean(client.PreImportPath)
+		clientsByPath[preImportPath] = append(clientsByPath[preImportPath], clientName)
+	}
+
+	targets, err := c.linkTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0)
+
+	for preImportPath, clientNames := range clientsByPath {
+		slices.Sort(clientNames)
+
+		recorded := packDirs(preImportPath, targets)
+		if len(recorded) == 0 {
+			continue
+		}
+
+		inUse, err := c.torrentPaths(ctx, clientNames)
+		if err != nil {
+			c.log.Error().Err(err).Msgf("skipping cleanup of %s", preImportPath)
+			continue
+		}
+
+		res, err := c.cleanPreImportPath(preImportPath, recorded, inUse, dryRun)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, res...)
+	}
+
+	return results, nil
+}
+
+// linkTargets returns the paths seasonpackarr linked or is about to link, which are the targets of the links in
+// the history and the targets of the pending matches.
+func (c *Cleaner) linkTargets(ctx context.Context) ([]string, error) {
+	targets, err := c.historyRepo.FindLinkTargets(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get link targets from history")
+	}
+
+	pending, err := c.matchRepo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get pending matches")
+	}
+
+	for _, pm := range pending {
+		for _, match := range pm.Matches {
+			targets = append(targets, match.AnnouncedEpPath)
+		}
+	}
+
+	return targets, nil
+}
+
+// packDirs returns the pack folders in preImportPath that contain any of the targets.
+func packDirs(preImportPath string, targets []string) map[string]struct{} {
+	dirs := make(map[string]struct{})
+
+	for _, target := range targets {
+		rel, err := filepath.Rel(preImportPath, target)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+
+		packName, _, ok := strings.Cut(rel, string(filepath.Separator))
+		if !ok {
+			// the target is a file directly in the pre import path
+			continue
+		}
+
+		dirs[filepath.Join(preImportPath, packName)] = struct{}{}
+	}
+
+	return dirs
+}
+
+// torrentPaths returns the content paths of the torrents of the given clients.
+func (c *Cleaner) torrentPaths(ctx context.Context, clientNames []string) ([]string, error) {
+	paths := make([]string, 0)
+
+	for _, clientName := range clientNames {
+		client, err := c.newClient(clientName)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create client %s", clientName)
+		}
+
+		if err = client.Login(ctx); err != nil {
+			return nil, errors.Wrap(err, "could not login to client %s", clientName)
+		}
+
+		torrents, err := client.GetTorrents(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get torrents of client %s", clientName)
+		}
+
+		for _, t := range torrents {
+			paths = append(paths, filepath.Clean(t.ContentPath))
+		}
+	}
+
+	return paths, nil
+}
+
+func (c *Cleaner) cleanPreImportPath(preImportPath string, recorded map[string]struct{}, inUse []string, dryRun bool) ([]Result, error) {
+	entries, err := os.ReadDir(preImportPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read pre import path %s", preImportPath)
+	}
+
+	gracePeriod := c.cfg.Config.Cleanup.GracePeriod
+	results := make([]Result, 0)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(preImportPath, entry.Name())
+		if _, ok := recorded[dir]; !ok {
+			c.log.Trace().Msgf("folder wasn't created by seasonpackarr: %s", dir)
+			continue
+		}
+
+		if slices.ContainsFunc(inUse, func(path string) bool { return overlaps(dir, path) }) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not stat %s", dir)
+		}
+
+		if time.Since(info.ModTime()) < gracePeriod {
+			c.log.Trace().Msgf("pack folder is still within the grace period: %s", dir)
+			continue
+		}
+
+		res, err := cleanDir(dir, dryRun)
+		if err != nil {
+			c.log.Error().Err(err).Msgf("error cleaning up pack folder: %s", dir)
+			continue
+		}
+
+		if dryRun {
+			c.log.Info().Msgf("would remove %d links from orphaned pack folder: %s", len(res.Removed), dir)
+		} else {
+			c.log.Info().Msgf("removed %d links from orphaned pack folder: %s", len(res.Removed), dir)
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// overlaps reports whether dir and path are the same, or one of them contains the other.
+func overlaps(dir, path string) bool {
+	return contains(dir, path) || contains(path, dir)
+}
+
+// contains reports whether path is parent or a path within it.
+func contains(parent, path string) bool {
+	rel, err := filepath.Rel(parent, path)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// cleanDir removes the symlinks and hardlinks in dir, files that only have a single link are kept so
+// no data is lost. Directories that are empty afterward are removed as well.
+func cleanDir(dir string, dryRun bool) (Result, error) {
+	res := Result{Dir: dir, Removed: make([]string, 0), Kept: make([]string, 0)}
+	dirs := make([]string, 0)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink == 0 {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			if n, ok := linkCount(info); !ok || n < 2 {
+				res.Kept = append(res.Kept, path)
+				return nil
+			}
+		}
+
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		res.Removed = append(res.Removed, path)
+
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if dryRun {
+		return res, nil
+	}
+
+	// remove the deepest directories first, removing non-empty directories fails which is fine
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+
+	return res, nil
+}
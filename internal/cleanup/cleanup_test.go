@@ -0,0 +1,238 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package cleanup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/clients"
+	"github.com/nuxencs/seasonpackarr/internal/config"
+	"github.com/nuxencs/seasonpackarr/internal/database"
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	torrents []clients.Torrent
+	err      error
+}
+
+func (c *fakeClient) Type() string                  { return "fake" }
+func (c *fakeClient) Login(_ context.Context) error { return c.err }
+func (c *fakeClient) GetTorrents(_ context.Context) ([]clients.Torrent, error) {
+	return c.torrents, nil
+}
+func (c *fakeClient) GetFiles(_ context.Context, _ string) ([]clients.File, error) { return nil, nil }
+func (c *fakeClient) AddTorrent(_ context.Context, _ []byte, _ clients.AddTorrentOptions) error {
+	return nil
+}
+
+// newPackDir creates a pack folder in preImportPath containing a hardlink to a file in libraryDir,
+// and a file that only has a single link.
+func newPackDir(t *testing.T, preImportPath, libraryDir, packName string, modTime time.Time) string {
+	t.Helper()
+
+	dir := filepath.Join(preImportPath, packName)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	src := filepath.Join(libraryDir, packName+".E01.mkv")
+	require.NoError(t, os.WriteFile(src, []byte("0"), 0644))
+	require.NoError(t, os.Link(src, filepath.Join(dir, "E01.mkv")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "E02.mkv"), []byte("0"), 0644))
+
+	require.NoError(t, os.Chtimes(dir, modTime, modTime))
+
+	return dir
+}
+
+func Test_Cleaner_Run(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+
+	tests := []struct {
+		name     string
+		packName string
+		modTime  time.Time
+		// recorded is where seasonpackarr recorded the pack folder, either "history" or "pending"
+		recorded string
+		// inUse is the content path of a torrent relative to the pack folder
+		inUse       string
+		clientErr   error
+		dryRun      bool
+		wantResults int
+		wantRemoved bool
+	}{
+		{
+			name:        "orphaned",
+			packName:    "Orphaned.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			recorded:    "history",
+			wantResults: 1,
+			wantRemoved: true,
+		},
+		{
+			name:        "orphaned_pending_match",
+			packName:    "Orphaned.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			recorded:    "pending",
+			wantResults: 1,
+			wantRemoved: true,
+		},
+		{
+			name:        "not_recorded",
+			packName:    "Foreign.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			wantResults: 0,
+		},
+		{
+			name:        "dry_run",
+			packName:    "Orphaned.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			recorded:    "history",
+			dryRun:      true,
+			wantResults: 1,
+			wantRemoved: false,
+		},
+		{
+			name:        "in_use",
+			packName:    "Used.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			recorded:    "history",
+			inUse:       ".",
+			wantResults: 0,
+		},
+		{
+			name:        "in_use_single_file",
+			packName:    "Used.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			recorded:    "history",
+			inUse:       "E01.mkv",
+			wantResults: 0,
+		},
+		{
+			name:        "grace_period",
+			packName:    "Fresh.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     time.Now(),
+			recorded:    "history",
+			wantResults: 0,
+		},
+		{
+			name:        "client_error",
+			packName:    "Orphaned.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			modTime:     old,
+			recorded:    "history",
+			clientErr:   errors.New("connection refused"),
+			wantResults: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preImportPath := t.TempDir()
+			libraryDir := t.TempDir()
+			dir := newPackDir(t, preImportPath, libraryDir, tt.packName, tt.modTime)
+
+			cfg := &config.AppConfig{Config: &domain.Config{
+				LogLevel: "ERROR",
+				Clients:  map[string]*domain.Client{"default": {PreImportPath: preImportPath}},
+				Cleanup:  domain.Cleanup{GracePeriod: 24 * time.Hour},
+			}}
+			log := logger.New(cfg.Config)
+
+			db, err := database.NewDB(&domain.Config{ConfigPath: t.TempDir()}, log)
+			require.NoError(t, err)
+			require.NoError(t, db.Open())
+			t.Cleanup(func() { db.Close() })
+
+			matchRepo := database.NewMatchRepo(log, db)
+			historyRepo := database.NewHistoryRepo(log, db)
+			target := filepath.Join(dir, "E01.mkv")
+
+			switch tt.recorded {
+			case "history":
+				h := domain.NewHistory(domain.HistoryActionPack)
+				h.ReleaseName = tt.packName
+				h.AddLink(filepath.Join(libraryDir, tt.packName+".E01.mkv"), target, domain.LinkTypeHardlink)
+				h.Finish(domain.StatusSuccessfulHardlink, nil)
+				require.NoError(t, historyRepo.Store(context.Background(), h))
+			case "pending":
+				require.NoError(t, matchRepo.Store(context.Background(), tt.packName, []domain.Match{{
+					ClientEpPath:    filepath.Join(libraryDir, tt.packName+".E01.mkv"),
+					ClientEpSize:    1,
+					AnnouncedEpPath: target,
+				}}))
+			}
+
+			client := &fakeClient{err: tt.clientErr}
+			if tt.inUse != "" {
+				client.torrents = []clients.Torrent{{
+					Name:        "Renamed.Title",
+					SavePath:    preImportPath,
+					ContentPath: filepath.Join(dir, tt.inUse),
+				}}
+			}
+
+			c := New(log, cfg, matchRepo, historyRepo)
+			c.newClient = func(_ string) (clients.TorrentClient, error) { return client, nil }
+
+			results, err := c.Run(context.Background(), tt.dryRun)
+			require.NoError(t, err)
+			require.Len(t, results, tt.wantResults)
+
+			if tt.wantResults > 0 {
+				assert.Equal(t, []string{filepath.Join(dir, "E01.mkv")}, results[0].Removed)
+				assert.Equal(t, []string{filepath.Join(dir, "E02.mkv")}, results[0].Kept)
+			}
+
+			_, err = os.Stat(filepath.Join(dir, "E01.mkv"))
+			assert.Equal(t, tt.wantRemoved, os.IsNotExist(err))
+
+			// the single link file and the library file are never removed
+			assert.FileExists(t, filepath.Join(dir, "E02.mkv"))
+			assert.FileExists(t, filepath.Join(libraryDir, tt.packName+".E01.mkv"))
+		})
+	}
+}
+
+func Test_packDirs(t *testing.T) {
+	preImportPath := filepath.Join("/data", "pre-import")
+
+	got := packDirs(preImportPath, []string{
+		filepath.Join(preImportPath, "Pack.A", "E01.mkv"),
+		filepath.Join(preImportPath, "Pack.A", "Subs", "E01.srt"),
+		filepath.Join(preImportPath, "Pack.B", "E01.mkv"),
+		filepath.Join(preImportPath, "E01.mkv"),
+		filepath.Join("/data", "other", "Pack.C", "E01.mkv"),
+	})
+	assert.Equal(t, map[string]struct{}{
+		filepath.Join(preImportPath, "Pack.A"): {},
+		filepath.Join(preImportPath, "Pack.B"): {},
+	}, got)
+}
+
+func Test_cleanDir(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(t.TempDir(), "src.mkv")
+	require.NoError(t, os.WriteFile(src, []byte("0"), 0644))
+
+	pack := filepath.Join(dir, "pack")
+	require.NoError(t, os.MkdirAll(filepath.Join(pack, "Subs"), 0755))
+	require.NoError(t, os.Link(src, filepath.Join(pack, "E01.mkv")))
+	require.NoError(t, os.Symlink(src, filepath.Join(pack, "Subs", "E01.srt")))
+
+	res, err := cleanDir(pack, false)
+	require.NoError(t, err)
+	assert.Len(t, res.Removed, 2)
+	assert.Empty(t, res.Kept)
+
+	// the emptied directories are removed as well
+	assert.NoDirExists(t, pack)
+	assert.FileExists(t, src)
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build !windows
+
+package cleanup
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// linkCount returns the number of hardlinks of a file.
+func linkCount(info fs.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return uint64(stat.Nlink), true
+}
@@ -0,0 +1,13 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build windows
+
+package cleanup
+
+import "io/fs"
+
+// linkCount isn't available on windows, so files are never removed there.
+func linkCount(_ fs.FileInfo) (uint64, bool) {
+	return 0, false
+}
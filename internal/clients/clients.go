@@ -0,0 +1,73 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+)
+
+type Torrent struct {
+	Hash     string
+	Name     string
+	SavePath string
+	// ContentPath is the folder of multi file torrents and the file of single file torrents.
+	ContentPath string
+	State       domain.TorrentState
+	Progress    float64
+	// Category is the category of qBittorrent and the label of deluge and rtorrent.
+	Category string
+	// Tags are the tags of qBittorrent and the labels of transmission.
+	Tags []string
+}
+
+type File struct {
+	// Path is relative to the save path of the torrent.
+	Path string
+	Size int64
+}
+
+type AddTorrentOptions struct {
+	SavePath     string
+	Category     string
+	Paused       bool
+	SkipChecking bool
+}
+
+// TorrentClient is the interface every supported torrent client has to implement.
+type TorrentClient interface {
+	Type() string
+	Login(ctx context.Context) error
+	GetTorrents(ctx context.Context) ([]Torrent, error)
+	GetFiles(ctx context.Context, hash string) ([]File, error)
+	AddTorrent(ctx context.Context, torrentBytes []byte, opts AddTorrentOptions) error
+}
+
+// New returns the TorrentClient matching the type of the given client config.
+func New(client *domain.Client, videoExtensions []string, sidecarExtensions []string) (TorrentClient, error) {
+	var c TorrentClient
+
+	switch client.Type {
+	case domain.ClientTypeQbittorrent, "":
+		c = newQbittorrent(client)
+	case domain.ClientTypeDeluge:
+		c = newDeluge(client)
+	case domain.ClientTypeTransmission:
+		c = newTransmission(client)
+	case domain.ClientTypeRtorrent:
+		c = newRtorrent(client)
+	case domain.ClientTypeFilesystem:
+		c = newFilesystem(client, videoExtensions, sidecarExtensions)
+	default:
+		return nil, fmt.Errorf("unsupported client type: %q", client.Type)
+	}
+
+	if len(client.PathMappings) > 0 {
+		c = newPathMappingClient(c, client.PathMappings)
+	}
+
+	return c, nil
+}
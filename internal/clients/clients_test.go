@@ -0,0 +1,116 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClientConfig returns a client config of the given type pointing to the fake server.
+func newClientConfig(t *testing.T, srv *httptest.Server, clientType string) *domain.Client {
+	t.Helper()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	host, portStr, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return &domain.Client{
+		Type: clientType,
+		Host: host,
+		Port: port,
+	}
+}
+
+func Test_MatchesFilters(t *testing.T) {
+	torrent := Torrent{
+		Name:     "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+		SavePath: "/data/torrents/tv",
+		Category: "tv-sonarr",
+		Tags:     []string{"sonarr", "tv"},
+	}
+
+	tests := []struct {
+		name    string
+		filters domain.TorrentFilters
+		want    bool
+	}{
+		{
+			name:    "no_filters",
+			filters: domain.TorrentFilters{},
+			want:    true,
+		},
+		{
+			name:    "included_category",
+			filters: domain.TorrentFilters{IncludeCategories: []string{"tv-sonarr", "tv-uhd"}},
+			want:    true,
+		},
+		{
+			name:    "not_included_category",
+			filters: domain.TorrentFilters{IncludeCategories: []string{"tv-uhd"}},
+			want:    false,
+		},
+		{
+			name:    "excluded_category",
+			filters: domain.TorrentFilters{ExcludeCategories: []string{"cross-seed", "tv-sonarr"}},
+			want:    false,
+		},
+		{
+			name:    "included_tag",
+			filters: domain.TorrentFilters{IncludeTags: []string{"tv"}},
+			want:    true,
+		},
+		{
+			name:    "not_included_tag",
+			filters: domain.TorrentFilters{IncludeTags: []string{"movies"}},
+			want:    false,
+		},
+		{
+			name:    "excluded_tag",
+			filters: domain.TorrentFilters{ExcludeTags: []string{"sonarr"}},
+			want:    false,
+		},
+		{
+			name:    "included_save_path",
+			filters: domain.TorrentFilters{IncludeSavePaths: []string{"/data/torrents/"}},
+			want:    true,
+		},
+		{
+			name:    "save_path_with_same_prefix",
+			filters: domain.TorrentFilters{IncludeSavePaths: []string{"/data/torrents/t"}},
+			want:    false,
+		},
+		{
+			name:    "excluded_save_path",
+			filters: domain.TorrentFilters{ExcludeSavePaths: []string{"/data/torrents/tv"}},
+			want:    false,
+		},
+		{
+			name: "include_and_exclude",
+			filters: domain.TorrentFilters{
+				IncludeCategories: []string{"tv-sonarr"},
+				ExcludeTags:       []string{"cross-seed"},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchesFilters(torrent, tt.filters))
+		})
+	}
+}
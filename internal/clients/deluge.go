@@ -0,0 +1,252 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+type delugeClient struct {
+	url      string
+	password string
+
+	httpClient *http.Client
+	id         atomic.Int64
+}
+
+type delugeRequest struct {
+	ID     int64  `json:"id"`
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+}
+
+type delugeResponse struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *delugeError    `json:"error"`
+}
+
+type delugeError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+type delugeTorrent struct {
+	Name     string  `json:"name"`
+	SavePath string  `json:"save_path"`
+	State    string  `json:"state"`
+	Progress float64 `json:"progress"`
+	// Label is only set if the label plugin is enabled.
+	Label string `json:"label"`
+}
+
+type delugeFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+func newDeluge(client *domain.Client) *delugeClient {
+	jar, _ := cookiejar.New(nil)
+
+	return &delugeClient{
+		url:      fmt.Sprintf("http://%s:%d/json", client.Host, client.Port),
+		password: client.Password,
+		httpClient: &http.Client{
+			Jar:     jar,
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (c *delugeClient) Type() string {
+	return domain.ClientTypeDeluge
+}
+
+func (c *delugeClient) Login(ctx context.Context) error {
+	var ok bool
+	if err := c.call(ctx, "auth.login", []any{c.password}, &ok); err != nil {
+		return errors.Wrap(err, "failed to login to deluge")
+	}
+
+	if !ok {
+		return errors.New("failed to login to deluge: wrong password")
+	}
+
+	var connected bool
+	if err := c.call(ctx, "web.connected", []any{}, &connected); err != nil {
+		return errors.Wrap(err, "failed to check deluge daemon connection")
+	}
+
+	if connected {
+		return nil
+	}
+
+	// the web ui isn't connected to a daemon yet, so we connect it to the first one it knows about
+	var hosts [][]any
+	if err := c.call(ctx, "web.get_hosts", []any{}, &hosts); err != nil {
+		return errors.Wrap(err, "failed to get deluge daemon hosts")
+	}
+
+	if len(hosts) == 0 || len(hosts[0]) == 0 {
+		return errors.New("failed to connect to deluge daemon: no hosts configured in web ui")
+	}
+
+	if err := c.call(ctx, "web.connect", []any{hosts[0][0]}, nil); err != nil {
+		return errors.Wrap(err, "failed to connect to deluge daemon")
+	}
+
+	return nil
+}
+
+func (c *delugeClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
+	var ts map[string]delugeTorrent
+	if err := c.call(ctx, "core.get_torrents_status", []any{
+		map[string]any{},
+		[]string{"name", "save_path", "state", "progress", "label"},
+	}, &ts); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]Torrent, 0, len(ts))
+	for hash, t := range ts {
+		torrents = append(torrents, Torrent{
+			Hash:        hash,
+			Name:        t.Name,
+			SavePath:    t.SavePath,
+			ContentPath: filepath.Join(t.SavePath, t.Name),
+			State:       delugeState(t.State),
+			// deluge reports progress as percentage
+			Progress: t.Progress / 100,
+			Category: t.Label,
+		})
+	}
+
+	return torrents, nil
+}
+
+func (c *delugeClient) GetFiles(ctx context.Context, hash string) ([]File, error) {
+	var status struct {
+		Files []delugeFile `json:"files"`
+	}
+	if err := c.call(ctx, "core.get_torrent_status", []any{hash, []string{"files"}}, &status); err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(status.Files))
+	for _, f := range status.Files {
+		files = append(files, File{
+			Path: f.Path,
+			Size: f.Size,
+		})
+	}
+
+	return files, nil
+}
+
+func (c *delugeClient) AddTorrent(ctx context.Context, torrentBytes []byte, opts AddTorrentOptions) error {
+	options := map[string]any{
+		"add_paused": opts.Paused,
+		"seed_mode":  opts.SkipChecking,
+	}
+
+	if opts.SavePath != "" {
+		options["download_location"] = opts.SavePath
+	}
+
+	var hash string
+	if err := c.call(ctx, "core.add_torrent_file", []any{
+		"seasonpackarr.torrent",
+		base64.StdEncoding.EncodeToString(torrentBytes),
+		options,
+	}, &hash); err != nil {
+		return err
+	}
+
+	// deluge doesn't have categories, the closest equivalent is a label of the label plugin
+	if opts.Category != "" {
+		if err := c.call(ctx, "label.set_torrent", []any{hash, opts.Category}, nil); err != nil {
+			return errors.Wrap(err, "failed to set label %q", opts.Category)
+		}
+	}
+
+	return nil
+}
+
+func (c *delugeClient) call(ctx context.Context, method string, params []any, result any) error {
+	body, err := json.Marshal(delugeRequest{
+		ID:     c.id.Add(1),
+		Method: method,
+		Params: params,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal request for method %s", method)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create request for method %s", method)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "request error for method %s", method)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status for method %s: %d", method, res.StatusCode)
+	}
+
+	var resp delugeResponse
+	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return errors.Wrap(err, "could not decode response for method %s", method)
+	}
+
+	if resp.Error != nil {
+		return errors.New("deluge error for method %s: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err = json.Unmarshal(resp.Result, result); err != nil {
+		return errors.Wrap(err, "could not decode result for method %s", method)
+	}
+
+	return nil
+}
+
+func delugeState(state string) domain.TorrentState {
+	switch state {
+	case "Downloading", "Allocating":
+		return domain.TorrentStateDownloading
+	case "Seeding":
+		return domain.TorrentStateSeeding
+	case "Paused":
+		return domain.TorrentStatePaused
+	case "Queued":
+		return domain.TorrentStateQueued
+	case "Checking", "Moving":
+		return domain.TorrentStateChecking
+	case "Error":
+		return domain.TorrentStateError
+	default:
+		return domain.TorrentStateUnknown
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeDeluge(t *testing.T, connected bool) (*httptest.Server, *domain.Client) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req delugeRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result any
+		switch req.Method {
+		case "auth.login":
+			result = req.Params[0] == "deluge"
+			if result == true {
+				http.SetCookie(w, &http.Cookie{Name: "_session_id", Value: "session"})
+			}
+		case "web.connected":
+			result = connected
+		case "web.get_hosts":
+			result = [][]any{{"hostid", "127.0.0.1", 58846, "Online"}}
+		case "web.connect":
+			connected = req.Params[0] == "hostid"
+		default:
+			if c, err := r.Cookie("_session_id"); err != nil || c.Value != "session" || !connected {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"id": req.ID, "result": nil, "error": map[string]any{"message": "Not authenticated", "code": 1},
+				})
+				return
+			}
+
+			switch req.Method {
+			case "core.get_torrents_status":
+				result = map[string]any{
+					"abc": map[string]any{
+						"name":      "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+						"save_path": "/data/torrents",
+						"state":     "Seeding",
+						"progress":  100.0,
+						"label":     "tv-sonarr",
+					},
+				}
+			case "core.get_torrent_status":
+				result = map[string]any{
+					"files": []map[string]any{
+						{"index": 0, "path": "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv", "size": 1000},
+					},
+				}
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": req.ID, "result": result, "error": nil})
+	}))
+
+	cfg := newClientConfig(t, srv, domain.ClientTypeDeluge)
+	cfg.Password = "deluge"
+
+	return srv, cfg
+}
+
+func Test_Deluge(t *testing.T) {
+	tests := []struct {
+		name      string
+		connected bool
+		password  string
+		wantErr   bool
+	}{
+		{
+			name:      "already_connected",
+			connected: true,
+			password:  "deluge",
+			wantErr:   false,
+		},
+		{
+			name:      "connect_to_daemon",
+			connected: false,
+			password:  "deluge",
+			wantErr:   false,
+		},
+		{
+			name:      "wrong_password",
+			connected: true,
+			password:  "wrong",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, cfg := newFakeDeluge(t, tt.connected)
+			defer srv.Close()
+
+			cfg.Password = tt.password
+
+			c, err := New(cfg, nil, nil)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+
+			err = c.Login(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			torrents, err := c.GetTorrents(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []Torrent{
+				{
+					Hash:        "abc",
+					Name:        "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					SavePath:    "/data/torrents",
+					ContentPath: "/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					State:       domain.TorrentStateSeeding,
+					Progress:    1,
+					Category:    "tv-sonarr",
+				},
+			}, torrents)
+
+			files, err := c.GetFiles(ctx, "abc")
+			require.NoError(t, err)
+			assert.Equal(t, []File{
+				{
+					Path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+					Size: 1000,
+				},
+			}, files)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/utils"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+// filesystemClient isn't a torrent client at all, it serves every episode file found in the
+// configured directories as a completed torrent. Sidecars next to an episode that share its name,
+// e.g. Show.S01E01.en.srt, are served as files of that torrent.
+type filesystemClient struct {
+	directories       []string
+	videoExtensions   []string
+	sidecarExtensions []string
+
+	files map[string][]File
+	m     sync.RWMutex
+}
+
+// newFilesystem creates a filesystem client that serves the files with the given video extensions as episodes,
+// the default ones are used if none are given. Sidecars are only served if sidecar extensions are given.
+func newFilesystem(client *domain.Client, videoExtensions []string, sidecarExtensions []string) *filesystemClient {
+	if len(videoExtensions) == 0 {
+		videoExtensions = domain.VideoExtensions
+	}
+
+	return &filesystemClient{
+		directories:       client.Directories,
+		videoExtensions:   videoExtensions,
+		sidecarExtensions: sidecarExtensions,
+		files:             make(map[string][]File),
+	}
+}
+
+func (c *filesystemClient) Type() string {
+	return domain.ClientTypeFilesystem
+}
+
+func (c *filesystemClient) Login(_ context.Context) error {
+	for _, dir := range c.directories {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return errors.Wrap(err, "failed to access directory %s", dir)
+		}
+
+		if !info.IsDir() {
+			return errors.New("not a directory: %s", dir)
+		}
+	}
+
+	return nil
+}
+
+func (c *filesystemClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
+	torrents := make([]Torrent, 0)
+	files := make(map[string][]File)
+	sidecars := make([]File, 0)
+
+	for _, dir := range c.directories {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			isVideo := utils.HasExtension(path, c.videoExtensions)
+			if !isVideo && !utils.HasExtension(path, c.sidecarExtensions) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			if !isVideo {
+				// sidecars keep their full path until they are assigned to an episode
+				sidecars = append(sidecars, File{Path: path, Size: info.Size()})
+				return nil
+			}
+
+			hash := filesystemHash(path)
+			files[hash] = []File{{
+				Path: filepath.Base(path),
+				Size: info.Size(),
+			}}
+
+			torrents = append(torrents, Torrent{
+				Hash:        hash,
+				Name:        strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+				SavePath:    filepath.Dir(path),
+				ContentPath: path,
+				State:       domain.TorrentStateSeeding,
+				Progress:    1,
+			})
+
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan directory %s", dir)
+		}
+	}
+
+	for _, sidecar := range sidecars {
+		hash, ok := sidecarHash(sidecar.Path, files, c.videoExtensions)
+		if !ok {
+			continue
+		}
+
+		files[hash] = append(files[hash], File{
+			Path: filepath.Base(sidecar.Path),
+			Size: sidecar.Size,
+		})
+	}
+
+	c.m.Lock()
+	c.files = files
+	c.m.Unlock()
+
+	return torrents, nil
+}
+
+func (c *filesystemClient) GetFiles(_ context.Context, hash string) ([]File, error) {
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	f, ok := c.files[hash]
+	if !ok {
+		return nil, errors.New("file not found: %s", hash)
+	}
+
+	return f, nil
+}
+
+func (c *filesystemClient) AddTorrent(_ context.Context, _ []byte, _ AddTorrentOptions) error {
+	return errors.New("adding torrents is not supported by the filesystem client")
+}
+
+// sidecarHash returns the hash of the episode in the same directory whose name the sidecar starts with,
+// e.g. Show.S01E01.mkv for Show.S01E01.en.srt.
+func sidecarHash(path string, files map[string][]File, videoExtensions []string) (string, bool) {
+	stem := path
+	for ext := filepath.Ext(stem); ext != ""; ext = filepath.Ext(stem) {
+		stem = strings.TrimSuffix(stem, ext)
+
+		for _, videoExt := range videoExtensions {
+			if hash := filesystemHash(stem + videoExt); len(files[hash]) > 0 {
+				return hash, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// filesystemHash returns a stable identifier for a file, since files don't have an info hash.
+func filesystemHash(path string) string {
+	h := sha1.Sum([]byte(path))
+	return hex.EncodeToString(h[:])
+}
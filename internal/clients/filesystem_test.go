@@ -0,0 +1,116 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Filesystem(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"Series Title/Season 01/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv":    "episode",
+		"Series Title/Season 01/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.nfo":    "nfo",
+		"Series Title/Season 01/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mp4":    "episode",
+		"Series Title/Season 01/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.en.srt": "subtitle",
+		"Series Title/Season 01/Unrelated.srt":                                        "subtitle",
+		"Series Title/Season 01/Series.Title.S01E03.txt":                              "text",
+		"Series Title/Season 01/Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp.webm":   "episode",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	c, err := New(&domain.Client{
+		Type:        domain.ClientTypeFilesystem,
+		Directories: []string{dir},
+	}, nil, domain.SidecarExtensions)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, c.Login(ctx))
+
+	torrents, err := c.GetTorrents(ctx)
+	require.NoError(t, err)
+	require.Len(t, torrents, 2)
+	assert.Equal(t, Torrent{
+		Hash:        filesystemHash(filepath.Join(dir, "Series Title/Season 01/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv")),
+		Name:        "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+		SavePath:    filepath.Join(dir, "Series Title/Season 01"),
+		ContentPath: filepath.Join(dir, "Series Title/Season 01/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv"),
+		State:       domain.TorrentStateSeeding,
+		Progress:    1,
+	}, torrents[0])
+
+	fs, err := c.GetFiles(ctx, torrents[0].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{
+			Path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			Size: int64(len("episode")),
+		},
+		{
+			Path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.nfo",
+			Size: int64(len("nfo")),
+		},
+	}, fs)
+
+	assert.Equal(t, "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp", torrents[1].Name)
+	fs, err = c.GetFiles(ctx, torrents[1].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{
+			Path: "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mp4",
+			Size: int64(len("episode")),
+		},
+		{
+			Path: "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.en.srt",
+			Size: int64(len("subtitle")),
+		},
+	}, fs)
+
+	_, err = c.GetFiles(ctx, "unknown")
+	assert.Error(t, err)
+
+	// only the configured extensions are indexed, without sidecar extensions no sidecars are served
+	c, err = New(&domain.Client{
+		Type:        domain.ClientTypeFilesystem,
+		Directories: []string{dir},
+	}, []string{".mkv", ".webm"}, nil)
+	require.NoError(t, err)
+
+	torrents, err = c.GetTorrents(ctx)
+	require.NoError(t, err)
+	require.Len(t, torrents, 2)
+	assert.Equal(t, "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp", torrents[0].Name)
+	assert.Equal(t, "Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp", torrents[1].Name)
+
+	fs, err = c.GetFiles(ctx, torrents[0].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{
+			Path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			Size: int64(len("episode")),
+		},
+	}, fs)
+
+	c, err = New(&domain.Client{
+		Type:        domain.ClientTypeFilesystem,
+		Directories: []string{filepath.Join(dir, "missing")},
+	}, nil, nil)
+	require.NoError(t, err)
+	assert.Error(t, c.Login(ctx))
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+)
+
+// MatchesFilters reports whether the torrent passes the filters of its client. Include filters only let torrents
+// through that match at least one of their values, exclude filters reject torrents that match any of them.
+func MatchesFilters(t Torrent, filters domain.TorrentFilters) bool {
+	if len(filters.IncludeCategories) > 0 && !slices.Contains(filters.IncludeCategories, t.Category) {
+		return false
+	}
+
+	if slices.Contains(filters.ExcludeCategories, t.Category) {
+		return false
+	}
+
+	if len(filters.IncludeTags) > 0 && !containsAny(filters.IncludeTags, t.Tags) {
+		return false
+	}
+
+	if containsAny(filters.ExcludeTags, t.Tags) {
+		return false
+	}
+
+	if len(filters.IncludeSavePaths) > 0 && !hasAnyPathPrefix(t.SavePath, filters.IncludeSavePaths) {
+		return false
+	}
+
+	return !hasAnyPathPrefix(t.SavePath, filters.ExcludeSavePaths)
+}
+
+// containsAny reports whether any of the values is in s.
+func containsAny(s []string, values []string) bool {
+	for _, v := range values {
+		if slices.Contains(s, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasAnyPathPrefix reports whether the path is inside any of the given directories.
+func hasAnyPathPrefix(path string, prefixes []string) bool {
+	path = filepath.Clean(path)
+
+	for _, prefix := range prefixes {
+		prefix = filepath.Clean(prefix)
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
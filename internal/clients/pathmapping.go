@@ -0,0 +1,97 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+)
+
+// pathMappingClient translates the paths of the wrapped client, e.g. if the client runs in a container
+// that sees the data at a different path than seasonpackarr does.
+type pathMappingClient struct {
+	TorrentClient
+	mappings []domain.PathMapping
+}
+
+func newPathMappingClient(client TorrentClient, mappings []domain.PathMapping) *pathMappingClient {
+	return &pathMappingClient{
+		TorrentClient: client,
+		mappings:      mappings,
+	}
+}
+
+func (c *pathMappingClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
+	torrents, err := c.TorrentClient.GetTorrents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range torrents {
+		torrents[i].SavePath = c.toLocal(torrents[i].SavePath)
+		torrents[i].ContentPath = c.toLocal(torrents[i].ContentPath)
+	}
+
+	return torrents, nil
+}
+
+func (c *pathMappingClient) AddTorrent(ctx context.Context, torrentBytes []byte, opts AddTorrentOptions) error {
+	if opts.SavePath != "" {
+		opts.SavePath = c.toRemote(opts.SavePath)
+	}
+
+	return c.TorrentClient.AddTorrent(ctx, torrentBytes, opts)
+}
+
+func (c *pathMappingClient) toLocal(path string) string {
+	return mapPath(path, c.mappings, func(m domain.PathMapping) (string, string) {
+		return m.RemotePath, m.LocalPath
+	})
+}
+
+func (c *pathMappingClient) toRemote(path string) string {
+	return mapPath(path, c.mappings, func(m domain.PathMapping) (string, string) {
+		return m.LocalPath, m.RemotePath
+	})
+}
+
+// mapPath replaces the longest matching prefix of path. Prefixes only match whole path
+// components, so "/data" matches "/data/tv" but not "/database". A root prefix like "/" matches
+// every absolute path.
+func mapPath(path string, mappings []domain.PathMapping, direction func(domain.PathMapping) (string, string)) string {
+	bestLen := -1
+	bestFrom, bestTo := "", ""
+
+	for _, m := range mappings {
+		from, to := direction(m)
+		if from == "" {
+			continue
+		}
+
+		// the root is trimmed to "", which is followed by a separator in every absolute path
+		from = strings.TrimRight(from, `/\`)
+
+		if len(from) <= bestLen {
+			continue
+		}
+
+		if path == from || strings.HasPrefix(path, from+"/") || strings.HasPrefix(path, from+`\`) {
+			bestLen, bestFrom, bestTo = len(from), from, to
+		}
+	}
+
+	if bestLen == -1 {
+		return path
+	}
+
+	rest := strings.TrimLeft(strings.TrimPrefix(path, bestFrom), `/\`)
+	if rest == "" {
+		return filepath.Clean(bestTo)
+	}
+
+	return filepath.Join(bestTo, filepath.FromSlash(strings.ReplaceAll(rest, `\`, "/")))
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PathMapping(t *testing.T) {
+	c := newPathMappingClient(nil, []domain.PathMapping{
+		{RemotePath: "/downloads", LocalPath: "/mnt/data/torrents"},
+		{RemotePath: "/downloads/tv/", LocalPath: "/mnt/tv"},
+	})
+
+	tests := []struct {
+		name       string
+		remotePath string
+		localPath  string
+	}{
+		{
+			name:       "mapped_path",
+			remotePath: "/downloads/movies",
+			localPath:  "/mnt/data/torrents/movies",
+		},
+		{
+			name:       "exact_path",
+			remotePath: "/downloads",
+			localPath:  "/mnt/data/torrents",
+		},
+		{
+			name:       "longest_prefix",
+			remotePath: "/downloads/tv/Series.Title.S01",
+			localPath:  "/mnt/tv/Series.Title.S01",
+		},
+		{
+			name:       "unmapped_path",
+			remotePath: "/data/torrents",
+			localPath:  "/data/torrents",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.localPath, c.toLocal(tt.remotePath), "toLocal(%s)", tt.remotePath)
+			assert.Equalf(t, tt.remotePath, c.toRemote(tt.localPath), "toRemote(%s)", tt.localPath)
+		})
+	}
+
+	// prefixes only match whole path components
+	assert.Equal(t, "/downloads2/tv", c.toLocal("/downloads2/tv"))
+
+	// the root maps every path, unless a longer prefix matches
+	c = newPathMappingClient(nil, []domain.PathMapping{
+		{RemotePath: "/", LocalPath: "/mnt/remote"},
+		{RemotePath: "/downloads", LocalPath: "/mnt/data/torrents"},
+	})
+	assert.Equal(t, "/mnt/remote/data/torrents", c.toLocal("/data/torrents"))
+	assert.Equal(t, "/mnt/remote", c.toLocal("/"))
+	assert.Equal(t, "/mnt/data/torrents/tv", c.toLocal("/downloads/tv"))
+	assert.Equal(t, "/data/torrents", c.toRemote("/mnt/remote/data/torrents"))
+
+	c = newPathMappingClient(nil, []domain.PathMapping{{RemotePath: "/data", LocalPath: "/"}})
+	assert.Equal(t, "/data/tv", c.toRemote("/tv"))
+}
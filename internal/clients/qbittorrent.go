@@ -0,0 +1,142 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/autobrr/go-qbittorrent"
+)
+
+type qbittorrentClient struct {
+	client *qbittorrent.Client
+}
+
+func newQbittorrent(client *domain.Client) *qbittorrentClient {
+	return &qbittorrentClient{
+		client: qbittorrent.NewClient(qbittorrent.Config{
+			Host:     fmt.Sprintf("http://%s:%d", client.Host, client.Port),
+			Username: client.Username,
+			Password: client.Password,
+		}),
+	}
+}
+
+func (c *qbittorrentClient) Type() string {
+	return domain.ClientTypeQbittorrent
+}
+
+func (c *qbittorrentClient) Login(ctx context.Context) error {
+	if err := c.client.LoginCtx(ctx); err != nil {
+		return errors.Wrap(err, "failed to login to qbittorrent")
+	}
+
+	return nil
+}
+
+func (c *qbittorrentClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
+	ts, err := c.client.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	torrents := make([]Torrent, 0, len(ts))
+	for _, t := range ts {
+		torrents = append(torrents, Torrent{
+			Hash:        t.Hash,
+			Name:        t.Name,
+			SavePath:    t.SavePath,
+			ContentPath: t.ContentPath,
+			State:       qbittorrentState(t.State),
+			Progress:    t.Progress,
+			Category:    t.Category,
+			Tags:        qbittorrentTags(t.Tags),
+		})
+	}
+
+	return torrents, nil
+}
+
+func (c *qbittorrentClient) GetFiles(ctx context.Context, hash string) ([]File, error) {
+	fs, err := c.client.GetFilesInformationCtx(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(*fs))
+	for _, f := range *fs {
+		files = append(files, File{
+			Path: f.Name,
+			Size: f.Size,
+		})
+	}
+
+	return files, nil
+}
+
+func (c *qbittorrentClient) AddTorrent(ctx context.Context, torrentBytes []byte, opts AddTorrentOptions) error {
+	options := map[string]string{
+		"paused":        strconv.FormatBool(opts.Paused),
+		"stopped":       strconv.FormatBool(opts.Paused),
+		"skip_checking": strconv.FormatBool(opts.SkipChecking),
+	}
+
+	if opts.SavePath != "" {
+		options["autoTMM"] = "false"
+		options["savepath"] = opts.SavePath
+	}
+
+	if opts.Category != "" {
+		options["category"] = opts.Category
+	}
+
+	return c.client.AddTorrentFromMemoryCtx(ctx, torrentBytes, options)
+}
+
+// qbittorrentTags splits the comma separated tags of a torrent.
+func qbittorrentTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+
+	split := strings.Split(tags, ",")
+	for i := range split {
+		split[i] = strings.TrimSpace(split[i])
+	}
+
+	return split
+}
+
+// qBittorrent 5 renamed the paused states to stopped, which go-qbittorrent doesn't expose yet.
+const (
+	qbittorrentStateStoppedDl qbittorrent.TorrentState = "stoppedDL"
+	qbittorrentStateStoppedUp qbittorrent.TorrentState = "stoppedUP"
+)
+
+func qbittorrentState(state qbittorrent.TorrentState) domain.TorrentState {
+	switch state {
+	case qbittorrent.TorrentStateDownloading, qbittorrent.TorrentStateStalledDl, qbittorrent.TorrentStateForcedDl,
+		qbittorrent.TorrentStateMetaDl, qbittorrent.TorrentStateAllocating:
+		return domain.TorrentStateDownloading
+	case qbittorrent.TorrentStateUploading, qbittorrent.TorrentStateStalledUp, qbittorrent.TorrentStateForcedUp:
+		return domain.TorrentStateSeeding
+	case qbittorrent.TorrentStatePausedDl, qbittorrent.TorrentStatePausedUp, qbittorrentStateStoppedDl, qbittorrentStateStoppedUp:
+		return domain.TorrentStatePaused
+	case qbittorrent.TorrentStateQueuedDl, qbittorrent.TorrentStateQueuedUp:
+		return domain.TorrentStateQueued
+	case qbittorrent.TorrentStateCheckingDl, qbittorrent.TorrentStateCheckingUp,
+		qbittorrent.TorrentStateCheckingResumeData, qbittorrent.TorrentStateMoving:
+		return domain.TorrentStateChecking
+	case qbittorrent.TorrentStateError, qbittorrent.TorrentStateMissingFiles:
+		return domain.TorrentStateError
+	default:
+		return domain.TorrentStateUnknown
+	}
+}
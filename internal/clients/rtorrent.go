@@ -0,0 +1,283 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+const rtorrentDefaultRPCPath = "/RPC2"
+
+type rtorrentClient struct {
+	host     string
+	port     int
+	rpcPath  string
+	scgi     bool
+	username string
+	password string
+
+	httpClient *http.Client
+	dialer     *net.Dialer
+}
+
+func newRtorrent(client *domain.Client) *rtorrentClient {
+	rpcPath := client.RPCPath
+	if rpcPath == "" {
+		rpcPath = rtorrentDefaultRPCPath
+	}
+
+	return &rtorrentClient{
+		host:     client.Host,
+		port:     client.Port,
+		rpcPath:  rpcPath,
+		scgi:     client.SCGI,
+		username: client.Username,
+		password: client.Password,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		dialer: &net.Dialer{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (c *rtorrentClient) Type() string {
+	return domain.ClientTypeRtorrent
+}
+
+func (c *rtorrentClient) Login(ctx context.Context) error {
+	// rtorrent has no login, fetching the version makes sure we can talk to it
+	if _, err := c.call(ctx, "system.client_version"); err != nil {
+		return errors.Wrap(err, "failed to connect to rtorrent")
+	}
+
+	return nil
+}
+
+func (c *rtorrentClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
+	res, err := c.call(ctx, "d.multicall2", "", "main",
+		"d.hash=", "d.name=", "d.directory=", "d.state=", "d.is_active=", "d.complete=",
+		"d.hashing=", "d.completed_bytes=", "d.size_bytes=", "d.custom1=", "d.is_multi_file=")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, ok := res.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected d.multicall2 response: %T", res)
+	}
+
+	torrents := make([]Torrent, 0, len(rows))
+	for _, row := range rows {
+		fields, ok := row.([]any)
+		if !ok || len(fields) != 11 {
+			return nil, fmt.Errorf("unexpected d.multicall2 row: %v", row)
+		}
+
+		var progress float64
+		if size := xmlrpcInt(fields[8]); size > 0 {
+			progress = float64(xmlrpcInt(fields[7])) / float64(size)
+		}
+
+		// the directory of multi file torrents already is the torrent folder
+		directory := xmlrpcString(fields[2])
+		contentPath := directory
+		if xmlrpcInt(fields[10]) == 0 {
+			contentPath = filepath.Join(directory, xmlrpcString(fields[1]))
+		}
+
+		torrents = append(torrents, Torrent{
+			Hash:        strings.ToLower(xmlrpcString(fields[0])),
+			Name:        xmlrpcString(fields[1]),
+			SavePath:    directory,
+			ContentPath: contentPath,
+			State:       rtorrentState(xmlrpcInt(fields[3]), xmlrpcInt(fields[4]), xmlrpcInt(fields[5]), xmlrpcInt(fields[6])),
+			Progress:    progress,
+			// ruTorrent stores the label of a torrent in custom1
+			Category: xmlrpcString(fields[9]),
+		})
+	}
+
+	return torrents, nil
+}
+
+func (c *rtorrentClient) GetFiles(ctx context.Context, hash string) ([]File, error) {
+	res, err := c.call(ctx, "f.multicall", strings.ToUpper(hash), "", "f.path=", "f.size_bytes=")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, ok := res.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected f.multicall response: %T", res)
+	}
+
+	files := make([]File, 0, len(rows))
+	for _, row := range rows {
+		fields, ok := row.([]any)
+		if !ok || len(fields) != 2 {
+			return nil, fmt.Errorf("unexpected f.multicall row: %v", row)
+		}
+
+		files = append(files, File{
+			Path: filepath.FromSlash(xmlrpcString(fields[0])),
+			Size: xmlrpcInt(fields[1]),
+		})
+	}
+
+	return files, nil
+}
+
+func (c *rtorrentClient) AddTorrent(ctx context.Context, torrentBytes []byte, opts AddTorrentOptions) error {
+	method := "load.raw_start"
+	if opts.Paused {
+		method = "load.raw"
+	}
+
+	params := []any{"", torrentBytes}
+
+	if opts.SavePath != "" {
+		params = append(params, fmt.Sprintf("d.directory.set=%q", opts.SavePath))
+	}
+
+	// rtorrent doesn't have categories, ruTorrent stores its labels in custom1
+	if opts.Category != "" {
+		params = append(params, fmt.Sprintf("d.custom1.set=%q", opts.Category))
+	}
+
+	_, err := c.call(ctx, method, params...)
+	return err
+}
+
+func (c *rtorrentClient) call(ctx context.Context, method string, params ...any) (any, error) {
+	body, err := encodeXmlrpcCall(method, params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode request for method %s", method)
+	}
+
+	var resp []byte
+	if c.scgi {
+		resp, err = c.doSCGI(ctx, body)
+	} else {
+		resp, err = c.doHTTP(ctx, body)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "request error for method %s", method)
+	}
+
+	res, err := decodeXmlrpcResponse(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "rtorrent error for method %s", method)
+	}
+
+	return res, nil
+}
+
+func (c *rtorrentClient) doHTTP(ctx context.Context, body []byte) ([]byte, error) {
+	url := fmt.Sprintf("http://%s:%d%s", c.host, c.port, c.rpcPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func (c *rtorrentClient) doSCGI(ctx context.Context, body []byte) ([]byte, error) {
+	network, address := "tcp", net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	// absolute paths point to a unix socket
+	if filepath.IsAbs(c.host) {
+		network, address = "unix", c.host
+	}
+
+	conn, err := c.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(c.httpClient.Timeout))
+	}
+
+	headers := fmt.Sprintf("CONTENT_LENGTH\x00%d\x00SCGI\x001\x00REQUEST_METHOD\x00POST\x00REQUEST_URI\x00%s\x00",
+		len(body), c.rpcPath)
+
+	if _, err = fmt.Fprintf(conn, "%d:%s,", len(headers), headers); err != nil {
+		return nil, err
+	}
+
+	if _, err = conn.Write(body); err != nil {
+		return nil, err
+	}
+
+	// the response is a cgi response, which consists of http like headers followed by the body
+	r := bufio.NewReader(conn)
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read scgi response headers")
+	}
+
+	if status := header.Get("Status"); status != "" && !strings.HasPrefix(status, "200") {
+		return nil, fmt.Errorf("unexpected status: %s", status)
+	}
+
+	return io.ReadAll(r)
+}
+
+func rtorrentState(state, active, complete, hashing int64) domain.TorrentState {
+	switch {
+	case hashing != 0:
+		return domain.TorrentStateChecking
+	case state == 0 || active == 0:
+		return domain.TorrentStatePaused
+	case complete != 0:
+		return domain.TorrentStateSeeding
+	default:
+		return domain.TorrentStateDownloading
+	}
+}
+
+func xmlrpcString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func xmlrpcInt(v any) int64 {
+	i, _ := v.(int64)
+	return i
+}
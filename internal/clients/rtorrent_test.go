@@ -0,0 +1,199 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rtorrentTorrentsResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse><params><param><value><array><data>
+<value><array><data>
+<value><string>ABC</string></value>
+<value><string>Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp</string></value>
+<value><string>/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp</string></value>
+<value><i8>1</i8></value>
+<value><i8>1</i8></value>
+<value><i8>1</i8></value>
+<value><i8>0</i8></value>
+<value><i8>1000</i8></value>
+<value><i8>1000</i8></value>
+<value><string>tv-sonarr</string></value>
+<value><i8>1</i8></value>
+</data></array></value>
+</data></array></value></param></params></methodResponse>`
+
+const rtorrentFilesResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse><params><param><value><array><data>
+<value><array><data>
+<value><string>Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv</string></value>
+<value><i8>1000</i8></value>
+</data></array></value>
+</data></array></value></param></params></methodResponse>`
+
+const rtorrentVersionResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse><params><param><value><string>0.9.8</string></value></param></params></methodResponse>`
+
+const rtorrentFaultResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse><fault><value><struct>
+<member><name>faultCode</name><value><i4>-506</i4></value></member>
+<member><name>faultString</name><value><string>Method 'foo' not defined</string></value></member>
+</struct></value></fault></methodResponse>`
+
+func fakeRtorrentResponse(t *testing.T, body io.Reader) string {
+	t.Helper()
+
+	var call xmlrpcMethodCall
+	require.NoError(t, xml.NewDecoder(body).Decode(&call))
+
+	switch call.MethodName {
+	case "system.client_version":
+		return rtorrentVersionResponse
+	case "d.multicall2":
+		return rtorrentTorrentsResponse
+	case "f.multicall":
+		if hash, _ := call.Params[0].Value.value(); hash == "ABC" {
+			return rtorrentFilesResponse
+		}
+	}
+
+	return rtorrentFaultResponse
+}
+
+func newFakeRtorrentHTTP(t *testing.T) *domain.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != rtorrentDefaultRPCPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = io.WriteString(w, fakeRtorrentResponse(t, r.Body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return newClientConfig(t, srv, domain.ClientTypeRtorrent)
+}
+
+func newFakeRtorrentSCGI(t *testing.T) *domain.Client {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				r := bufio.NewReader(conn)
+
+				// read netstring encoded headers
+				lenStr, err := r.ReadString(':')
+				if err != nil {
+					return
+				}
+				headerLen, _ := strconv.Atoi(strings.TrimSuffix(lenStr, ":"))
+
+				headers := make([]byte, headerLen+1)
+				if _, err = io.ReadFull(r, headers); err != nil {
+					return
+				}
+
+				fields := strings.Split(string(headers[:headerLen]), "\x00")
+				contentLen, _ := strconv.Atoi(fields[1])
+
+				resp := fakeRtorrentResponse(t, io.LimitReader(r, int64(contentLen)))
+				_, _ = fmt.Fprintf(conn, "Status: 200 OK\r\nContent-Type: text/xml\r\nContent-Length: %d\r\n\r\n%s", len(resp), resp)
+			}()
+		}
+	}()
+
+	host, portStr, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return &domain.Client{
+		Type: domain.ClientTypeRtorrent,
+		Host: host,
+		Port: port,
+		SCGI: true,
+	}
+}
+
+func Test_Rtorrent(t *testing.T) {
+	tests := []struct {
+		name   string
+		client func(t *testing.T) *domain.Client
+	}{
+		{
+			name:   "http",
+			client: newFakeRtorrentHTTP,
+		},
+		{
+			name:   "scgi",
+			client: newFakeRtorrentSCGI,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.client(t), nil, nil)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+
+			require.NoError(t, c.Login(ctx))
+
+			torrents, err := c.GetTorrents(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []Torrent{
+				{
+					Hash:        "abc",
+					Name:        "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					SavePath:    "/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					ContentPath: "/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					State:       domain.TorrentStateSeeding,
+					Progress:    1,
+					Category:    "tv-sonarr",
+				},
+			}, torrents)
+
+			files, err := c.GetFiles(ctx, "abc")
+			require.NoError(t, err)
+			assert.Equal(t, []File{
+				{
+					Path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+					Size: 1000,
+				},
+			}, files)
+
+			_, err = c.GetFiles(ctx, "def")
+			assert.ErrorContains(t, err, "Method 'foo' not defined")
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+const transmissionSessionIdHeader = "X-Transmission-Session-Id"
+
+type transmissionClient struct {
+	url      string
+	username string
+	password string
+
+	httpClient *http.Client
+	sessionId  string
+	m          sync.RWMutex
+}
+
+type transmissionRequest struct {
+	Method    string `json:"method"`
+	Arguments any    `json:"arguments,omitempty"`
+}
+
+type transmissionResponse struct {
+	Result    string          `json:"result"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type transmissionTorrent struct {
+	HashString  string             `json:"hashString"`
+	Name        string             `json:"name"`
+	DownloadDir string             `json:"downloadDir"`
+	PercentDone float64            `json:"percentDone"`
+	Status      int                `json:"status"`
+	Error       int                `json:"error"`
+	Labels      []string           `json:"labels"`
+	Files       []transmissionFile `json:"files"`
+}
+
+type transmissionFile struct {
+	Name   string `json:"name"`
+	Length int64  `json:"length"`
+}
+
+func newTransmission(client *domain.Client) *transmissionClient {
+	return &transmissionClient{
+		url:      fmt.Sprintf("http://%s:%d/transmission/rpc", client.Host, client.Port),
+		username: client.Username,
+		password: client.Password,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (c *transmissionClient) Type() string {
+	return domain.ClientTypeTransmission
+}
+
+func (c *transmissionClient) Login(ctx context.Context) error {
+	// transmission has no login, session-get makes sure the credentials are valid and fetches the session id
+	if err := c.call(ctx, "session-get", nil, nil); err != nil {
+		return errors.Wrap(err, "failed to login to transmission")
+	}
+
+	return nil
+}
+
+func (c *transmissionClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
+	var res struct {
+		Torrents []transmissionTorrent `json:"torrents"`
+	}
+	if err := c.call(ctx, "torrent-get", map[string]any{
+		"fields": []string{"hashString", "name", "downloadDir", "percentDone", "status", "error", "labels"},
+	}, &res); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]Torrent, 0, len(res.Torrents))
+	for _, t := range res.Torrents {
+		torrents = append(torrents, Torrent{
+			Hash:        t.HashString,
+			Name:        t.Name,
+			SavePath:    t.DownloadDir,
+			ContentPath: filepath.Join(t.DownloadDir, t.Name),
+			State:       transmissionState(t.Status, t.Error),
+			Progress:    t.PercentDone,
+			Tags:        t.Labels,
+		})
+	}
+
+	return torrents, nil
+}
+
+func (c *transmissionClient) GetFiles(ctx context.Context, hash string) ([]File, error) {
+	var res struct {
+		Torrents []transmissionTorrent `json:"torrents"`
+	}
+	if err := c.call(ctx, "torrent-get", map[string]any{
+		"ids":    []string{hash},
+		"fields": []string{"hashString", "files"},
+	}, &res); err != nil {
+		return nil, err
+	}
+
+	if len(res.Torrents) == 0 {
+		return nil, errors.New("torrent not found: %s", hash)
+	}
+
+	files := make([]File, 0, len(res.Torrents[0].Files))
+	for _, f := range res.Torrents[0].Files {
+		files = append(files, File{
+			Path: f.Name,
+			Size: f.Length,
+		})
+	}
+
+	return files, nil
+}
+
+func (c *transmissionClient) AddTorrent(ctx context.Context, torrentBytes []byte, opts AddTorrentOptions) error {
+	args := map[string]any{
+		"metainfo": base64.StdEncoding.EncodeToString(torrentBytes),
+		"paused":   opts.Paused,
+	}
+
+	if opts.SavePath != "" {
+		args["download-dir"] = opts.SavePath
+	}
+
+	// transmission doesn't have categories, the closest equivalent are labels
+	if opts.Category != "" {
+		args["labels"] = []string{opts.Category}
+	}
+
+	return c.call(ctx, "torrent-add", args, nil)
+}
+
+func (c *transmissionClient) call(ctx context.Context, method string, arguments any, result any) error {
+	body, err := json.Marshal(transmissionRequest{
+		Method:    method,
+		Arguments: arguments,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal request for method %s", method)
+	}
+
+	// transmission answers with 409 and a new session id if ours is missing or expired,
+	// so we retry the request once with the new session id
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+		if err != nil {
+			return errors.Wrap(err, "could not create request for method %s", method)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		if c.username != "" || c.password != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		c.m.RLock()
+		req.Header.Set(transmissionSessionIdHeader, c.sessionId)
+		c.m.RUnlock()
+
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			return errors.Wrap(err, "request error for method %s", method)
+		}
+
+		if res.StatusCode == http.StatusConflict {
+			res.Body.Close()
+
+			c.m.Lock()
+			c.sessionId = res.Header.Get(transmissionSessionIdHeader)
+			c.m.Unlock()
+
+			continue
+		}
+
+		return c.decode(res, method, result)
+	}
+
+	return errors.New("could not get session id for method %s", method)
+}
+
+func (c *transmissionClient) decode(res *http.Response, method string, result any) error {
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status for method %s: %d", method, res.StatusCode)
+	}
+
+	var resp transmissionResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return errors.Wrap(err, "could not decode response for method %s", method)
+	}
+
+	if resp.Result != "success" {
+		return errors.New("transmission error for method %s: %s", method, resp.Result)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Arguments, result); err != nil {
+		return errors.Wrap(err, "could not decode arguments for method %s", method)
+	}
+
+	return nil
+}
+
+func transmissionState(status int, errorCode int) domain.TorrentState {
+	if errorCode != 0 {
+		return domain.TorrentStateError
+	}
+
+	switch status {
+	case 0:
+		return domain.TorrentStatePaused
+	case 1, 2:
+		return domain.TorrentStateChecking
+	case 3, 5:
+		return domain.TorrentStateQueued
+	case 4:
+		return domain.TorrentStateDownloading
+	case 6:
+		return domain.TorrentStateSeeding
+	default:
+		return domain.TorrentStateUnknown
+	}
+}
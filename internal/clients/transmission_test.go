@@ -0,0 +1,129 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeTransmission(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "transmission" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get(transmissionSessionIdHeader) != "session" {
+			w.Header().Set(transmissionSessionIdHeader, "session")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var req struct {
+			Method    string         `json:"method"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		args := map[string]any{}
+		switch req.Method {
+		case "torrent-get":
+			torrent := map[string]any{
+				"hashString":  "abc",
+				"name":        "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+				"downloadDir": "/data/torrents",
+				"percentDone": 0.5,
+				"status":      4,
+				"error":       0,
+				"labels":      []string{"sonarr", "tv"},
+			}
+			if _, ok := req.Arguments["ids"]; ok {
+				torrent = map[string]any{
+					"hashString": "abc",
+					"files": []map[string]any{
+						{"name": "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv", "length": 1000, "bytesCompleted": 500},
+					},
+				}
+			}
+			args["torrents"] = []map[string]any{torrent}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": "success", "arguments": args})
+	}))
+}
+
+func Test_Transmission(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "valid_credentials",
+			password: "transmission",
+			wantErr:  false,
+		},
+		{
+			name:     "wrong_password",
+			password: "wrong",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeTransmission(t)
+			defer srv.Close()
+
+			cfg := newClientConfig(t, srv, domain.ClientTypeTransmission)
+			cfg.Username = "admin"
+			cfg.Password = tt.password
+
+			c, err := New(cfg, nil, nil)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+
+			err = c.Login(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			torrents, err := c.GetTorrents(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []Torrent{
+				{
+					Hash:        "abc",
+					Name:        "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					SavePath:    "/data/torrents",
+					ContentPath: "/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+					State:       domain.TorrentStateDownloading,
+					Progress:    0.5,
+					Tags:        []string{"sonarr", "tv"},
+				},
+			}, torrents)
+
+			files, err := c.GetFiles(ctx, "abc")
+			require.NoError(t, err)
+			assert.Equal(t, []File{
+				{
+					Path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+					Size: 1000,
+				},
+			}, files)
+		})
+	}
+}
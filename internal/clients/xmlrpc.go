@@ -0,0 +1,187 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package clients
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+// xmlrpcValue is used for both directions of the minimal xml-rpc implementation needed for rtorrent.
+type xmlrpcValue struct {
+	String  *string       `xml:"string"`
+	Int     *string       `xml:"int"`
+	I4      *string       `xml:"i4"`
+	I8      *string       `xml:"i8"`
+	Boolean *string       `xml:"boolean"`
+	Double  *string       `xml:"double"`
+	Base64  *string       `xml:"base64"`
+	Array   *xmlrpcArray  `xml:"array"`
+	Struct  *xmlrpcStruct `xml:"struct"`
+	Raw     string        `xml:",chardata"`
+}
+
+type xmlrpcArray struct {
+	Data []xmlrpcValue `xml:"data>value"`
+}
+
+type xmlrpcStruct struct {
+	Members []xmlrpcMember `xml:"member"`
+}
+
+type xmlrpcMember struct {
+	Name  string      `xml:"name"`
+	Value xmlrpcValue `xml:"value"`
+}
+
+type xmlrpcParam struct {
+	Value xmlrpcValue `xml:"value"`
+}
+
+type xmlrpcMethodCall struct {
+	XMLName    xml.Name      `xml:"methodCall"`
+	MethodName string        `xml:"methodName"`
+	Params     []xmlrpcParam `xml:"params>param"`
+}
+
+type xmlrpcMethodResponse struct {
+	XMLName xml.Name      `xml:"methodResponse"`
+	Params  []xmlrpcParam `xml:"params>param"`
+	Fault   *xmlrpcValue  `xml:"fault>value"`
+}
+
+func encodeXmlrpcCall(method string, params ...any) ([]byte, error) {
+	call := xmlrpcMethodCall{
+		MethodName: method,
+		Params:     make([]xmlrpcParam, 0, len(params)),
+	}
+
+	for _, param := range params {
+		v, err := newXmlrpcValue(param)
+		if err != nil {
+			return nil, err
+		}
+		call.Params = append(call.Params, xmlrpcParam{Value: v})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(call); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeXmlrpcResponse(body []byte) (any, error) {
+	var resp xmlrpcMethodResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "could not decode xml-rpc response")
+	}
+
+	if resp.Fault != nil {
+		fault, _ := resp.Fault.value()
+		if m, ok := fault.(map[string]any); ok {
+			return nil, errors.New("xml-rpc fault: %v (code %v)", m["faultString"], m["faultCode"])
+		}
+		return nil, errors.New("xml-rpc fault: %v", fault)
+	}
+
+	if len(resp.Params) == 0 {
+		return nil, nil
+	}
+
+	return resp.Params[0].Value.value()
+}
+
+func newXmlrpcValue(v any) (xmlrpcValue, error) {
+	switch t := v.(type) {
+	case string:
+		return xmlrpcValue{String: &t}, nil
+	case int:
+		s := strconv.Itoa(t)
+		return xmlrpcValue{I8: &s}, nil
+	case int64:
+		s := strconv.FormatInt(t, 10)
+		return xmlrpcValue{I8: &s}, nil
+	case bool:
+		s := "0"
+		if t {
+			s = "1"
+		}
+		return xmlrpcValue{Boolean: &s}, nil
+	case []byte:
+		s := base64.StdEncoding.EncodeToString(t)
+		return xmlrpcValue{Base64: &s}, nil
+	case []string:
+		arr := &xmlrpcArray{Data: make([]xmlrpcValue, 0, len(t))}
+		for _, s := range t {
+			arr.Data = append(arr.Data, xmlrpcValue{String: &s})
+		}
+		return xmlrpcValue{Array: arr}, nil
+	case []any:
+		arr := &xmlrpcArray{Data: make([]xmlrpcValue, 0, len(t))}
+		for _, e := range t {
+			ev, err := newXmlrpcValue(e)
+			if err != nil {
+				return xmlrpcValue{}, err
+			}
+			arr.Data = append(arr.Data, ev)
+		}
+		return xmlrpcValue{Array: arr}, nil
+	default:
+		return xmlrpcValue{}, fmt.Errorf("unsupported xml-rpc type: %T", v)
+	}
+}
+
+func (v xmlrpcValue) value() (any, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil, v.I4 != nil, v.I8 != nil:
+		s := v.Int
+		if s == nil {
+			s = v.I4
+		}
+		if s == nil {
+			s = v.I8
+		}
+		return strconv.ParseInt(strings.TrimSpace(*s), 10, 64)
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1", nil
+	case v.Double != nil:
+		return strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+	case v.Base64 != nil:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(*v.Base64))
+	case v.Array != nil:
+		arr := make([]any, 0, len(v.Array.Data))
+		for _, e := range v.Array.Data {
+			ev, err := e.value()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, ev)
+		}
+		return arr, nil
+	case v.Struct != nil:
+		m := make(map[string]any, len(v.Struct.Members))
+		for _, member := range v.Struct.Members {
+			mv, err := member.Value.value()
+			if err != nil {
+				return nil, err
+			}
+			m[member.Name] = mv
+		}
+		return m, nil
+	default:
+		// values without a type are strings
+		return v.Raw, nil
+	}
+}
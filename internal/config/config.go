@@ -12,13 +12,17 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/nuxencs/seasonpackarr/internal/domain"
 	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/internal/utils"
 	"github.com/nuxencs/seasonpackarr/pkg/errors"
 
 	"github.com/fsnotify/fsnotify"
@@ -47,41 +51,110 @@ clients:
   # Default: default
   #
   default:
-    # qBittorrent Hostname / IP
+    # Client Type
+    #
+    # Default: "qbittorrent"
+    #
+    # Options: "qbittorrent", "deluge", "transmission", "rtorrent", "filesystem"
+    #
+    type: "qbittorrent"
+
+    # Client Hostname / IP
     #
     # Default: "127.0.0.1"
     #
     host: "127.0.0.1"
 
-    # qBittorrent Port
+    # Client Port
     #
     # Default: 8080
     #
     port: 8080
 
-    # qBittorrent Username
+    # Client Username
+    # Not used by Deluge, which only requires the password of the web ui
     #
     # Default: "admin"
     #
     username: "admin"
 
-    # qBittorrent Password
+    # Client Password
     #
     # Default: "adminadmin"
     #
     password: "adminadmin"
 
-    # Pre Import Path of qBittorrent for Sonarr
+    # Pre Import Path of the client for Sonarr
     # Needs to be filled out correctly, e.g. "/data/torrents/tv-hd"
     #
     # Default: ""
     #
     preImportPath: ""
 
-  # Below you can find an example on how to define a second qBittorrent client
+    # RPC Path
+    # Only used by rTorrent, path of the XML-RPC endpoint when connecting over HTTP
+    #
+    # Default: "/RPC2"
+    #
+    # rpcPath: "/RPC2"
+
+    # SCGI
+    # Only used by rTorrent, connects to the SCGI port instead of the HTTP endpoint
+    # If host is an absolute path, e.g. "/home/user/.rtorrent.sock", it will be used as unix socket
+    #
+    # Default: false
+    #
+    # scgi: false
+
+    # Directories
+    # Only used by the filesystem client, directories that will be scanned for episodes instead of asking a torrent client
+    #
+    # Default: []
+    #
+    # directories: [ "/data/media/tv" ]
+
+    # Path Mappings
+    # Translates the paths reported by the client to the paths seasonpackarr sees, e.g. if the client runs in a container
+    # The remote path is the path the client sees, the local path is the path seasonpackarr sees
+    #
+    # Optional
+    #
+    # pathMappings:
+    #   - remotePath: "/downloads"
+    #     localPath: "/mnt/data/torrents"
+
+    # Link Types
+    # How episodes are linked into the pre import path, the link types are tried in the given order until one succeeds
+    # Hardlinks and reflinks only work if the pre import path is on the same filesystem as the episodes
+    #
+    # Default: [ "hardlink" ]
+    #
+    # Options: "hardlink", "reflink", "symlink", "copy"
+    #
+    # linkTypes: [ "hardlink", "reflink", "copy" ]
+
+    # Filters
+    # Only torrents that pass the filters are used as matches, e.g. to never link episodes of cross-seeds
+    # Include filters only let torrents through that match at least one value, exclude filters reject torrents that match any
+    # Categories are the categories of qBittorrent and the labels of Deluge and rTorrent, tags are the tags of qBittorrent
+    # and the labels of Transmission. Save paths match the save path and all directories inside it, after path mappings
+    #
+    # Optional
+    #
+    # filters:
+    #   includeCategories: [ "tv-sonarr" ]
+    #   excludeCategories: [ "cross-seed", "archive" ]
+    #   includeTags: []
+    #   excludeTags: [ "cross-seed" ]
+    #   includeSavePaths: [ "/data/torrents/tv" ]
+    #   excludeSavePaths: []
+
+  # Below you can find an example on how to define a second client
   # If you want to define even more clients just copy this segment and adjust the values accordingly
   #
   #multi_client_example:
+  #  type: "qbittorrent"
+  #
   #  host: "127.0.0.1"
   #
   #  port: 9090
@@ -138,6 +211,24 @@ logLevel: "DEBUG"
 #
 # smartModeThreshold: 0.75
 
+# Anime Mode
+# Toggles resolving absolute episode numbers, e.g. "Show - 13", to season and episode numbers using TVMaze
+# Only affects releases and files that don't contain a season
+#
+# Default: false
+#
+# animeMode: false
+
+# Specials
+# Sets how specials (S00) are handled
+# "ignore" never matches specials and skips extras folders like Extras or Featurettes in the season pack
+# "match" matches specials, but only links them if they are present in the season pack, which requires parseTorrentFile
+# "count" is like "match", but matched specials also count towards the smart mode threshold
+#
+# Default: "ignore"
+#
+# specials: "ignore"
+
 # Parse Torrent File
 # Toggles torrent file parsing to get the correct folder name
 #
@@ -145,6 +236,105 @@ logLevel: "DEBUG"
 #
 # parseTorrentFile: false
 
+# Video Extensions
+# Sets the file extensions of the video files that are matched as episodes
+#
+# Default: [ ".mkv", ".mp4", ".avi", ".ts", ".m2ts", ".m4v", ".wmv" ]
+#
+# videoExtensions: [ ".mkv", ".mp4", ".avi", ".ts", ".m2ts", ".m4v", ".wmv" ]
+
+# Sidecar Extensions
+# Sets the file extensions of the sidecar files, e.g. subtitles, that are linked along with the episodes
+# Sidecars are only linked if the season pack contains a file with the same name and size, which requires parseTorrentFile
+# Leave empty to disable sidecar matching
+#
+# Default: []
+#
+# sidecarExtensions: [ ".srt", ".ass", ".nfo" ]
+
+# Exclusions
+# Decides which video files are junk and never treated as episodes, neither in your client nor in the season pack
+# If a torrent in your client contains multiple videos, every one that isn't excluded is treated as its own episode
+#
+# exclusions:
+  # Samples
+  # Toggles excluding samples, i.e. files in sample folders and files named like "rlsgrp-show-s01e01-sample.mkv"
+  #
+  # Default: true
+  #
+  # samples: true
+
+  # Min Size
+  # Excludes files smaller than the given size in MB, set to 0 to disable
+  #
+  # Default: 0
+  #
+  # minSize: 50
+
+  # Patterns
+  # Excludes files whose path matches any of the given regular expressions
+  #
+  # Default: []
+  #
+  # patterns: [ "(?i)\\.trailer\\." ]
+
+# Torrent Health
+# Decides which torrents in your client can be used as matches, the others are rejected as incomplete or errored
+#
+# torrentHealth:
+  # Require Complete
+  # Toggles only using torrents that are completely downloaded, so half written files are never linked
+  #
+  # Default: true
+  #
+  # requireComplete: true
+
+  # Excluded States
+  # Torrents in any of the given states are never used
+  #
+  # Default: [ "error", "checking" ]
+  #
+  # Options: "downloading", "seeding", "paused", "queued", "checking", "error", "unknown"
+  #
+  # excludedStates: [ "error", "checking" ]
+
+# Match TTL
+# Sets how long the matches of a season pack are kept while waiting for the parse request
+# Expired matches are removed periodically, set to 0 to keep them until they are used
+#
+# Default: 1h
+#
+# matchTTL: 1h
+
+# Transactional Linking
+# Toggles all-or-nothing linking, if linking a single episode fails all links and folders created for the
+# season pack are removed again, so the torrent client never starts a season pack in a partially linked folder
+#
+# Default: false
+#
+# transactionalLinking: false
+
+# Cleanup
+# Removes pack folders from the pre import paths that no torrent in the client points at, e.g. if autobrr didn't grab
+# the season pack in the end. Only folders that seasonpackarr linked into are considered, and only symlinks and
+# hardlinks are removed, never the last copy of a file
+# You can also run it manually with "seasonpackarr cleanup --dry-run"
+#
+# cleanup:
+  # Interval
+  # How often the cleanup runs, set to 0 to disable the schedule
+  #
+  # Default: 0
+  #
+  # interval: 6h
+
+  # Grace Period
+  # How old a pack folder needs to be before it can be removed
+  #
+  # Default: 24h
+  #
+  # gracePeriod: 24h
+
 # Fuzzy Matching
 # You can decide for which criteria the matching should be less strict, e.g. repack status and HDR format
 #
@@ -299,7 +489,60 @@ func New(configPath string, version string) *AppConfig {
 	c.load(configPath)
 	c.loadFromEnv()
 
+	c.Config.VideoExtensions = utils.NormalizeExtensions(c.Config.VideoExtensions)
+	if len(c.Config.VideoExtensions) == 0 {
+		log.Fatalf("videoExtensions can't be empty, please provide at least one extension like .mkv")
+	}
+	c.Config.SidecarExtensions = utils.NormalizeExtensions(c.Config.SidecarExtensions)
+
+	if c.Config.Exclusions.MinSize < 0 {
+		log.Fatalf("exclusions minSize can't be negative, please use 0 to disable it")
+	}
+
+	for _, state := range c.Config.TorrentHealth.ExcludedStates {
+		if !slices.Contains(domain.TorrentStates, domain.TorrentState(state)) {
+			log.Fatalf("torrent state %q is not supported, please use one of: %s", state, strings.Join(torrentStates(), ", "))
+		}
+	}
+
+	for _, pattern := range c.Config.Exclusions.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			log.Fatalf("exclusion pattern %q is not a valid regular expression: %v", pattern, err)
+		}
+	}
+
+	if !slices.Contains(domain.SpecialsPolicies, c.Config.Specials) {
+		log.Fatalf("specials %q is not supported, please use one of: %s", c.Config.Specials, strings.Join(domain.SpecialsPolicies, ", "))
+	}
+
 	for clientName, client := range c.Config.Clients {
+		if client.Type == "" {
+			client.Type = domain.ClientTypeQbittorrent
+		}
+
+		if !slices.Contains(domain.ClientTypes, client.Type) {
+			log.Fatalf("type %q for client %q is not supported, please use one of: %s", client.Type, clientName, strings.Join(domain.ClientTypes, ", "))
+		}
+
+		if client.Type == domain.ClientTypeFilesystem && len(client.Directories) == 0 {
+			log.Fatalf("directories for client %q can't be empty, please provide at least one directory containing episodes", clientName)
+		}
+
+		// files on disk have neither categories nor tags, so nothing would ever match
+		if client.Type == domain.ClientTypeFilesystem && (len(client.Filters.IncludeCategories) > 0 || len(client.Filters.IncludeTags) > 0) {
+			log.Fatalf("includeCategories and includeTags for client %q aren't supported by the filesystem client, please use includeSavePaths instead", clientName)
+		}
+
+		if len(client.LinkTypes) == 0 {
+			client.LinkTypes = []string{domain.LinkTypeHardlink}
+		}
+
+		for _, linkType := range client.LinkTypes {
+			if !slices.Contains(domain.LinkTypes, linkType) {
+				log.Fatalf("link type %q for client %q is not supported, please use one of: %s", linkType, clientName, strings.Join(domain.LinkTypes, ", "))
+			}
+		}
+
 		if client.PreImportPath == "" {
 			log.Fatalf("preImportPath for client %q can't be empty, please provide a valid path to the directory you want seasonpacks to be hardlinked to", clientName)
 		}
@@ -312,6 +555,16 @@ func New(configPath string, version string) *AppConfig {
 	return c
 }
 
+// torrentStates returns the names of all torrent states.
+func torrentStates() []string {
+	states := make([]string, 0, len(domain.TorrentStates))
+	for _, state := range domain.TorrentStates {
+		states = append(states, string(state))
+	}
+
+	return states
+}
+
 func (c *AppConfig) defaults() {
 	viper.SetDefault("host", "0.0.0.0")
 	viper.SetDefault("port", 42069)
@@ -322,7 +575,20 @@ func (c *AppConfig) defaults() {
 	viper.SetDefault("logMaxBackups", 3)
 	viper.SetDefault("smartMode", false)
 	viper.SetDefault("smartModeThreshold", 0.75)
+	viper.SetDefault("animeMode", false)
+	viper.SetDefault("specials", domain.SpecialsIgnore)
 	viper.SetDefault("parseTorrentFile", false)
+	viper.SetDefault("videoExtensions", domain.VideoExtensions)
+	viper.SetDefault("sidecarExtensions", []string{})
+	viper.SetDefault("exclusions.samples", true)
+	viper.SetDefault("exclusions.minSize", 0)
+	viper.SetDefault("exclusions.patterns", []string{})
+	viper.SetDefault("torrentHealth.requireComplete", true)
+	viper.SetDefault("torrentHealth.excludedStates", []string{string(domain.TorrentStateError), string(domain.TorrentStateChecking)})
+	viper.SetDefault("matchTTL", "1h")
+	viper.SetDefault("transactionalLinking", false)
+	viper.SetDefault("cleanup.interval", "0")
+	viper.SetDefault("cleanup.gracePeriod", "24h")
 	viper.SetDefault("fuzzyMatching.skipRepackCompare", false)
 	viper.SetDefault("fuzzyMatching.simplifyHdrCompare", false)
 	viper.SetDefault("apiToken", "")
@@ -366,10 +632,40 @@ func (c *AppConfig) loadFromEnv() {
 					if f, _ := strconv.ParseFloat(envPair[1], 32); f > 0 {
 						c.Config.SmartModeThreshold = float32(f)
 					}
+				case prefix + "ANIME_MODE":
+					if b, err := strconv.ParseBool(envPair[1]); err == nil {
+						c.Config.AnimeMode = b
+					}
+				case prefix + "SPECIALS":
+					c.Config.Specials = envPair[1]
 				case prefix + "PARSE_TORRENT_FILE":
 					if b, err := strconv.ParseBool(envPair[1]); err == nil {
 						c.Config.ParseTorrentFile = b
 					}
+				case prefix + "VIDEO_EXTENSIONS":
+					c.Config.VideoExtensions = strings.Split(envPair[1], ",")
+				case prefix + "SIDECAR_EXTENSIONS":
+					c.Config.SidecarExtensions = strings.Split(envPair[1], ",")
+				case prefix + "EXCLUSIONS_SAMPLES":
+					if b, err := strconv.ParseBool(envPair[1]); err == nil {
+						c.Config.Exclusions.Samples = b
+					}
+				case prefix + "EXCLUSIONS_MIN_SIZE":
+					if i, err := strconv.Atoi(envPair[1]); err == nil {
+						c.Config.Exclusions.MinSize = i
+					}
+				case prefix + "TORRENT_HEALTH_REQUIRE_COMPLETE":
+					if b, err := strconv.ParseBool(envPair[1]); err == nil {
+						c.Config.TorrentHealth.RequireComplete = b
+					}
+				case prefix + "TRANSACTIONAL_LINKING":
+					if b, err := strconv.ParseBool(envPair[1]); err == nil {
+						c.Config.TransactionalLinking = b
+					}
+				case prefix + "MATCH_TTL":
+					if d, err := time.ParseDuration(envPair[1]); err == nil && d >= 0 {
+						c.Config.MatchTTL = d
+					}
 				case prefix + "API_TOKEN":
 					c.Config.APIToken = envPair[1]
 				}
@@ -408,6 +704,11 @@ func (c *AppConfig) load(configPath string) {
 	if err := viper.Unmarshal(c.Config); err != nil {
 		log.Fatalf("Could not unmarshal config file: %v: err %q", viper.ConfigFileUsed(), err)
 	}
+
+	// use the directory of the found config file, e.g. to store the database next to it
+	if c.Config.ConfigPath == "" {
+		c.Config.ConfigPath = filepath.Dir(viper.ConfigFileUsed())
+	}
 }
 
 func (c *AppConfig) DynamicReload(log logger.Logger) {
@@ -427,9 +728,62 @@ func (c *AppConfig) DynamicReload(log logger.Logger) {
 		smartModeThreshold := viper.GetFloat64("smartModeThreshold")
 		c.Config.SmartModeThreshold = float32(smartModeThreshold)
 
+		animeMode := viper.GetBool("animeMode")
+		c.Config.AnimeMode = animeMode
+
+		if specials := viper.GetString("specials"); slices.Contains(domain.SpecialsPolicies, specials) {
+			c.Config.Specials = specials
+		} else {
+			log.Error().Msgf("specials %q is not supported, keeping %q, please use one of: %s", specials,
+				c.Config.Specials, strings.Join(domain.SpecialsPolicies, ", "))
+		}
+
 		parseTorrentFile := viper.GetBool("parseTorrentFile")
 		c.Config.ParseTorrentFile = parseTorrentFile
 
+		if videoExtensions := utils.NormalizeExtensions(viper.GetStringSlice("videoExtensions")); len(videoExtensions) > 0 {
+			c.Config.VideoExtensions = videoExtensions
+		}
+
+		sidecarExtensions := utils.NormalizeExtensions(viper.GetStringSlice("sidecarExtensions"))
+		c.Config.SidecarExtensions = sidecarExtensions
+
+		excludeSamples := viper.GetBool("exclusions.samples")
+		c.Config.Exclusions.Samples = excludeSamples
+
+		if minSize := viper.GetInt("exclusions.minSize"); minSize >= 0 {
+			c.Config.Exclusions.MinSize = minSize
+		}
+
+		requireComplete := viper.GetBool("torrentHealth.requireComplete")
+		c.Config.TorrentHealth.RequireComplete = requireComplete
+
+		excludedStates := viper.GetStringSlice("torrentHealth.excludedStates")
+		if invalid := slices.IndexFunc(excludedStates, func(state string) bool {
+			return !slices.Contains(domain.TorrentStates, domain.TorrentState(state))
+		}); invalid != -1 {
+			log.Error().Msgf("torrent state %q is not supported, keeping the previous excluded states", excludedStates[invalid])
+		} else {
+			c.Config.TorrentHealth.ExcludedStates = excludedStates
+		}
+
+		// invalid patterns keep the previous ones, since they would otherwise only fail once a request arrives
+		excludePatterns := viper.GetStringSlice("exclusions.patterns")
+		if invalid := slices.IndexFunc(excludePatterns, func(pattern string) bool {
+			_, err := regexp.Compile(pattern)
+			return err != nil
+		}); invalid != -1 {
+			log.Error().Msgf("exclusion pattern %q is not a valid regular expression, keeping the previous ones", excludePatterns[invalid])
+		} else {
+			c.Config.Exclusions.Patterns = excludePatterns
+		}
+
+		matchTTL := viper.GetDuration("matchTTL")
+		c.Config.MatchTTL = matchTTL
+
+		transactionalLinking := viper.GetBool("transactionalLinking")
+		c.Config.TransactionalLinking = transactionalLinking
+
 		skipRepackCompare := viper.GetBool("fuzzyMatching.skipRepackCompare")
 		c.Config.FuzzyMatching.SkipRepackCompare = skipRepackCompare
 
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// Code is modified for use with seasonpackarr
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type DB struct {
+	log     zerolog.Logger
+	handler *sql.DB
+	ctx     context.Context
+	cancel  func()
+
+	DSN string
+}
+
+func NewDB(cfg *domain.Config, log logger.Logger) (*DB, error) {
+	db := &DB{
+		log: log.With().Str("module", "database").Logger(),
+	}
+	db.ctx, db.cancel = context.WithCancel(context.Background())
+
+	if cfg.ConfigPath == "" {
+		return nil, errors.New("config path can't be empty")
+	}
+
+	db.DSN = dataSourceName(cfg.ConfigPath, "seasonpackarr.db")
+
+	return db, nil
+}
+
+func (db *DB) Open() error {
+	if db.DSN == "" {
+		return errors.New("DSN required")
+	}
+
+	return db.openSQLite()
+}
+
+func (db *DB) Close() error {
+	// cancel background context
+	db.cancel()
+
+	// close database
+	if db.handler != nil {
+		return db.handler.Close()
+	}
+
+	return nil
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return db.handler.BeginTx(ctx, opts)
+}
+
+func dataSourceName(configPath string, name string) string {
+	if configPath != "" {
+		return filepath.Join(configPath, name)
+	}
+
+	return name
+}
+
+func (db *DB) String() string {
+	return fmt.Sprintf("sqlite: %s", db.DSN)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type HistoryRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewHistoryRepo(log logger.Logger, db *DB) domain.HistoryRepo {
+	return &HistoryRepo{
+		log: log.With().Str("repo", "history").Logger(),
+		db:  db,
+	}
+}
+
+func (r *HistoryRepo) Store(ctx context.Context, h *domain.History) error {
+	rejections, err := json.Marshal(h.Rejections)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling rejections")
+	}
+
+	matches, err := json.Marshal(h.Matches)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling matches")
+	}
+
+	links, err := json.Marshal(h.Links)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling links")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx,
+		`INSERT INTO history (action, release_name, client, status_code, error, dry_run, rejections, matches, links,
+		found_episodes, total_episodes, duration_ms, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		h.Action, h.ReleaseName, h.Client, int(h.StatusCode), toNullString(h.Error), h.DryRun,
+		string(rejections), string(matches), string(links), h.FoundEpisodes, h.TotalEpisodes, h.DurationMs, h.CreatedAt.UTC())
+	if err != nil {
+		return errors.Wrap(err, "error inserting history")
+	}
+
+	if h.ID, err = res.LastInsertId(); err != nil {
+		return errors.Wrap(err, "error getting history id")
+	}
+
+	r.log.Trace().Msgf("stored history %d for release: %s", h.ID, h.ReleaseName)
+
+	return nil
+}
+
+func (r *HistoryRepo) Find(ctx context.Context, params domain.HistoryQueryParams) (*domain.HistoryList, error) {
+	where := make([]string, 0)
+	args := make([]any, 0)
+
+	if params.Client != "" {
+		where = append(where, "client = ?")
+		args = append(args, params.Client)
+	}
+
+	if params.StatusCode != 0 {
+		where = append(where, "status_code = ?")
+		args = append(args, int(params.StatusCode))
+	}
+
+	if params.Search != "" {
+		where = append(where, `release_name LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(params.Search)+"%")
+	}
+
+	if !params.From.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, params.From.UTC())
+	}
+
+	if !params.To.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, params.To.UTC())
+	}
+
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	list := &domain.HistoryList{Data: make([]domain.History, 0)}
+
+	if err := r.db.handler.QueryRowContext(ctx, `SELECT COUNT(*) FROM history `+whereClause, args...).Scan(&list.TotalCount); err != nil {
+		return nil, errors.Wrap(err, "error counting history")
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx,
+		`SELECT id, action, release_name, client, status_code, error, dry_run, found_episodes, total_episodes, duration_ms, created_at FROM history `+
+			whereClause+` ORDER BY id DESC LIMIT ? OFFSET ?`, append(args, limit, params.Offset)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h domain.History
+		var statusCode int
+		var errStr sql.NullString
+
+		if err = rows.Scan(&h.ID, &h.Action, &h.ReleaseName, &h.Client, &statusCode, &errStr, &h.DryRun,
+			&h.FoundEpisodes, &h.TotalEpisodes, &h.DurationMs, &h.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		h.StatusCode = domain.StatusCode(statusCode)
+		h.Status = h.StatusCode.String()
+		h.Error = errStr.String
+
+		list.Data = append(list.Data, h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating rows")
+	}
+
+	return list, nil
+}
+
+func (r *HistoryRepo) FindByID(ctx context.Context, id int64) (*domain.History, error) {
+	var h domain.History
+	var statusCode int
+	var errStr sql.NullString
+	var rejections, matches, links string
+
+	err := r.db.handler.QueryRowContext(ctx,
+		`SELECT id, action, release_name, client, status_code, error, dry_run, rejections, matches, links,
+		found_episodes, total_episodes, duration_ms, created_at FROM history WHERE id = ?`, id).
+		Scan(&h.ID, &h.Action, &h.ReleaseName, &h.Client, &statusCode, &errStr, &h.DryRun, &rejections, &matches, &links,
+			&h.FoundEpisodes, &h.TotalEpisodes, &h.DurationMs, &h.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrHistoryNotFound
+		}
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	h.StatusCode = domain.StatusCode(statusCode)
+	h.Status = h.StatusCode.String()
+	h.Error = errStr.String
+
+	if err = json.Unmarshal([]byte(rejections), &h.Rejections); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling rejections")
+	}
+
+	if err = json.Unmarshal([]byte(matches), &h.Matches); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling matches")
+	}
+
+	if err = json.Unmarshal([]byte(links), &h.Links); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling links")
+	}
+
+	return &h, nil
+}
+
+// FindLinkTargets returns the targets of all links that were created, links of dry runs are left out since
+// they were never created.
+func (r *HistoryRepo) FindLinkTargets(ctx context.Context) ([]string, error) {
+	rows, err := r.db.handler.QueryContext(ctx, `SELECT links FROM history WHERE dry_run = FALSE`)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	targets := make([]string, 0)
+	for rows.Next() {
+		var links string
+		if err = rows.Scan(&links); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		var historyLinks []domain.HistoryLink
+		if err = json.Unmarshal([]byte(links), &historyLinks); err != nil {
+			return nil, errors.Wrap(err, "error unmarshaling links")
+		}
+
+		for _, link := range historyLinks {
+			targets = append(targets, link.Target)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating rows")
+	}
+
+	return targets, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern, so they are matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func toNullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
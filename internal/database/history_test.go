@@ -0,0 +1,198 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HistoryRepo_Store(t *testing.T) {
+	db, log := setupTestDB(t)
+	repo := NewHistoryRepo(log, db)
+	ctx := context.Background()
+
+	h := domain.NewHistory(domain.HistoryActionPack)
+	h.ReleaseName = "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	h.Client = "default"
+	h.AddRejection("Series.Title.S01E01.1080p.WEB-DL.H.264-OtherGrp", "", domain.CompareInfo{
+		StatusCode:   domain.StatusRlsGrpMismatch,
+		RejectValueA: "RlsGrp",
+		RejectValueB: "OtherGrp",
+	})
+	h.AddMatch(domain.HistoryMatch{Season: 1, Episode: 2, Path: "/data/torrents/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv", Size: 1000})
+	h.AddLink("/data/torrents/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+		"/data/pre-import/Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv", domain.LinkTypeHardlink)
+	h.Finish(domain.StatusSuccessfulHardlink, nil)
+
+	require.NoError(t, repo.Store(ctx, h))
+	assert.Equal(t, int64(1), h.ID)
+
+	var releaseName, rejections, links string
+	var statusCode int
+	require.NoError(t, db.handler.QueryRowContext(ctx,
+		`SELECT release_name, status_code, rejections, links FROM history WHERE id = ?`, h.ID).
+		Scan(&releaseName, &statusCode, &rejections, &links))
+
+	assert.Equal(t, h.ReleaseName, releaseName)
+	assert.Equal(t, int(domain.StatusSuccessfulHardlink), statusCode)
+	assert.JSONEq(t, `[{"candidate":"Series.Title.S01E01.1080p.WEB-DL.H.264-OtherGrp","statusCode":203,"reason":"release group did not match","rejectValueA":"RlsGrp","rejectValueB":"OtherGrp"}]`, rejections)
+	assert.Contains(t, links, "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv")
+}
+
+func Test_HistoryRepo_Find(t *testing.T) {
+	db, log := setupTestDB(t)
+	repo := NewHistoryRepo(log, db)
+	ctx := context.Background()
+
+	entries := []struct {
+		releaseName string
+		client      string
+		statusCode  domain.StatusCode
+	}{
+		{"Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp", "default", domain.StatusSuccessfulHardlink},
+		{"Series.Title.S02.1080p.WEB-DL.H.264-RlsGrp", "default", domain.StatusNoMatches},
+		{"Other.Title.S01.1080p.WEB-DL.H.264-RlsGrp", "secondary", domain.StatusNoMatches},
+		{"Other_Title.S02.1080p.WEB-DL.H.264-RlsGrp", "secondary", domain.StatusBelowThreshold},
+	}
+	for _, e := range entries {
+		h := domain.NewHistory(domain.HistoryActionPack)
+		h.ReleaseName = e.releaseName
+		h.Client = e.client
+		h.AddMatch(domain.HistoryMatch{Path: "/data/torrents/" + e.releaseName})
+		h.Finish(e.statusCode, nil)
+		require.NoError(t, repo.Store(ctx, h))
+	}
+
+	tests := []struct {
+		name      string
+		params    domain.HistoryQueryParams
+		wantIDs   []int64
+		wantTotal int
+	}{
+		{
+			name:      "all",
+			params:    domain.HistoryQueryParams{},
+			wantIDs:   []int64{4, 3, 2, 1},
+			wantTotal: 4,
+		},
+		{
+			name:      "client",
+			params:    domain.HistoryQueryParams{Client: "secondary"},
+			wantIDs:   []int64{4, 3},
+			wantTotal: 2,
+		},
+		{
+			name:      "status_code",
+			params:    domain.HistoryQueryParams{StatusCode: domain.StatusNoMatches},
+			wantIDs:   []int64{3, 2},
+			wantTotal: 2,
+		},
+		{
+			name:      "search",
+			params:    domain.HistoryQueryParams{Search: "series.title"},
+			wantIDs:   []int64{2, 1},
+			wantTotal: 2,
+		},
+		{
+			name:      "search_wildcard_is_literal",
+			params:    domain.HistoryQueryParams{Search: "Other_"},
+			wantIDs:   []int64{4},
+			wantTotal: 1,
+		},
+		{
+			name:      "time_range",
+			params:    domain.HistoryQueryParams{From: time.Now().Add(time.Hour)},
+			wantIDs:   []int64{},
+			wantTotal: 0,
+		},
+		{
+			name:      "pagination",
+			params:    domain.HistoryQueryParams{Limit: 2, Offset: 1},
+			wantIDs:   []int64{3, 2},
+			wantTotal: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Find(ctx, tt.params)
+			require.NoError(t, err)
+
+			ids := make([]int64, 0)
+			for _, h := range got.Data {
+				ids = append(ids, h.ID)
+				assert.Empty(t, h.Matches)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantTotal, got.TotalCount)
+		})
+	}
+}
+
+func Test_HistoryRepo_FindByID(t *testing.T) {
+	db, log := setupTestDB(t)
+	repo := NewHistoryRepo(log, db)
+	ctx := context.Background()
+
+	h := domain.NewHistory(domain.HistoryActionParse)
+	h.ReleaseName = "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	h.Client = "default"
+	h.AddRejection("/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+		"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv", domain.CompareInfo{
+			StatusCode:   domain.StatusEpisodeMismatch,
+			RejectValueA: 1,
+			RejectValueB: 2,
+		})
+	h.Finish(domain.StatusFailedMatchToTorrentEps, domain.StatusFailedMatchToTorrentEps.Error())
+	require.NoError(t, repo.Store(ctx, h))
+
+	got, err := repo.FindByID(ctx, h.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HistoryActionParse, got.Action)
+	assert.Equal(t, domain.StatusFailedMatchToTorrentEps, got.StatusCode)
+	assert.Equal(t, "could not match episodes to files in pack", got.Error)
+	require.Len(t, got.Rejections, 1)
+	assert.Equal(t, domain.StatusEpisodeMismatch, got.Rejections[0].StatusCode)
+	// the reject values are decoded from json
+	assert.Equal(t, float64(1), got.Rejections[0].RejectValueA)
+	assert.WithinDuration(t, h.CreatedAt, got.CreatedAt, time.Second)
+
+	_, err = repo.FindByID(ctx, 42)
+	assert.ErrorIs(t, err, domain.ErrHistoryNotFound)
+}
+
+func Test_HistoryRepo_FindLinkTargets(t *testing.T) {
+	db, log := setupTestDB(t)
+	repo := NewHistoryRepo(log, db)
+	ctx := context.Background()
+
+	for _, dryRun := range []bool{false, true} {
+		h := domain.NewHistory(domain.HistoryActionPack)
+		h.ReleaseName = "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+		h.Client = "default"
+		h.DryRun = dryRun
+		h.AddLink("/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			"/data/pre-import/Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv", domain.LinkTypeHardlink)
+		h.Finish(domain.StatusSuccessfulHardlink, nil)
+		require.NoError(t, repo.Store(ctx, h))
+	}
+
+	h := domain.NewHistory(domain.HistoryActionPack)
+	h.ReleaseName = "Other.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	h.Finish(domain.StatusNoMatches, domain.StatusNoMatches.Error())
+	require.NoError(t, repo.Store(ctx, h))
+
+	// the links of dry runs were never created
+	got, err := repo.FindLinkTargets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/data/pre-import/Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+	}, got)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type MatchRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewMatchRepo(log logger.Logger, db *DB) domain.MatchRepo {
+	return &MatchRepo{
+		log: log.With().Str("repo", "match").Logger(),
+		db:  db,
+	}
+}
+
+// Store replaces all pending matches of a release with the given matches.
+func (r *MatchRepo) Store(ctx context.Context, releaseName string, matches []domain.Match) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error beginning transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM pending_match WHERE release_name = ?`, releaseName); err != nil {
+		return errors.Wrap(err, "error deleting pending matches")
+	}
+
+	createdAt := time.Now().UTC()
+
+	for _, m := range matches {
+		if _, err = tx.ExecContext(ctx,
+			`INSERT INTO pending_match (release_name, client_ep_path, client_ep_size, announced_ep_path, created_at) VALUES (?, ?, ?, ?, ?)`,
+			releaseName, m.ClientEpPath, m.ClientEpSize, m.AnnouncedEpPath, createdAt); err != nil {
+			return errors.Wrap(err, "error inserting pending match")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "error committing transaction")
+	}
+
+	r.log.Trace().Msgf("stored %d pending matches for release: %s", len(matches), releaseName)
+
+	return nil
+}
+
+func (r *MatchRepo) FindAll(ctx context.Context) ([]domain.PendingMatch, error) {
+	rows, err := r.db.handler.QueryContext(ctx,
+		`SELECT release_name, client_ep_path, client_ep_size, announced_ep_path, created_at FROM pending_match ORDER BY id`)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+	defer rows.Close()
+
+	pending := make([]domain.PendingMatch, 0)
+	index := make(map[string]int)
+
+	for rows.Next() {
+		var releaseName string
+		var createdAt time.Time
+		var m domain.Match
+
+		if err = rows.Scan(&releaseName, &m.ClientEpPath, &m.ClientEpSize, &m.AnnouncedEpPath, &createdAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		i, ok := index[releaseName]
+		if !ok {
+			i = len(pending)
+			index[releaseName] = i
+			pending = append(pending, domain.PendingMatch{ReleaseName: releaseName, CreatedAt: createdAt})
+		}
+
+		pending[i].Matches = append(pending[i].Matches, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating rows")
+	}
+
+	return pending, nil
+}
+
+func (r *MatchRepo) Delete(ctx context.Context, releaseName string) error {
+	if _, err := r.db.handler.ExecContext(ctx, `DELETE FROM pending_match WHERE release_name = ?`, releaseName); err != nil {
+		return errors.Wrap(err, "error deleting pending matches")
+	}
+
+	r.log.Trace().Msgf("deleted pending matches for release: %s", releaseName)
+
+	return nil
+}
+
+func (r *MatchRepo) DeleteOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.db.handler.ExecContext(ctx, `DELETE FROM pending_match WHERE created_at < ?`, t.UTC())
+	if err != nil {
+		return 0, errors.Wrap(err, "error deleting expired pending matches")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting affected rows")
+	}
+
+	r.log.Trace().Msgf("deleted %d expired pending match rows", rows)
+
+	return rows, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) (*DB, logger.Logger) {
+	t.Helper()
+
+	cfg := &domain.Config{ConfigPath: t.TempDir(), LogLevel: "ERROR"}
+	log := logger.New(cfg)
+
+	db, err := NewDB(cfg, log)
+	require.NoError(t, err)
+	require.NoError(t, db.Open())
+	t.Cleanup(func() { db.Close() })
+
+	return db, log
+}
+
+func Test_MatchRepo(t *testing.T) {
+	db, log := setupTestDB(t)
+	repo := NewMatchRepo(log, db)
+	ctx := context.Background()
+
+	matches := []domain.Match{
+		{
+			ClientEpPath:    "/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			ClientEpSize:    1000,
+			AnnouncedEpPath: "/data/pre-import/Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+		},
+		{
+			ClientEpPath:    "/data/torrents/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			ClientEpSize:    2000,
+			AnnouncedEpPath: "/data/pre-import/Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+		},
+	}
+
+	require.NoError(t, repo.Store(ctx, "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp", matches))
+	require.NoError(t, repo.Store(ctx, "Other.Title.S01.1080p.WEB-DL.H.264-RlsGrp", matches[:1]))
+
+	// storing again replaces the existing matches
+	require.NoError(t, repo.Store(ctx, "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp", matches))
+
+	got, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Other.Title.S01.1080p.WEB-DL.H.264-RlsGrp", got[0].ReleaseName)
+	assert.Equal(t, matches[:1], got[0].Matches)
+	assert.Equal(t, "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp", got[1].ReleaseName)
+	assert.Equal(t, matches, got[1].Matches)
+	assert.WithinDuration(t, time.Now(), got[1].CreatedAt, time.Minute)
+
+	require.NoError(t, repo.Delete(ctx, "Other.Title.S01.1080p.WEB-DL.H.264-RlsGrp"))
+
+	got, err = repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp", got[0].ReleaseName)
+
+	// reopening the database keeps the matches
+	require.NoError(t, db.Close())
+	require.NoError(t, db.Open())
+
+	got, err = repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	// only matches created before the given time are deleted
+	n, err := repo.DeleteOlderThan(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	n, err = repo.DeleteOlderThan(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	got, err = repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
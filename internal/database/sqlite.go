@@ -0,0 +1,100 @@
+// Copyright (c) 2021 - 2024, Ludvig Lundgren and the autobrr contributors.
+// Code is modified for use with seasonpackarr
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	_ "modernc.org/sqlite"
+)
+
+func (db *DB) openSQLite() error {
+	if db.DSN == "" {
+		return errors.New("DSN required")
+	}
+
+	var err error
+
+	// open database connection
+	if db.handler, err = sql.Open("sqlite", db.DSN); err != nil {
+		return errors.Wrap(err, "could not open db connection")
+	}
+
+	// Set busy timeout
+	if _, err = db.handler.Exec(`PRAGMA busy_timeout = 5000;`); err != nil {
+		return errors.Wrap(err, "busy timeout pragma")
+	}
+
+	// Enable WAL. SQLite supports concurrent readers but only a single writer.
+	// In WAL mode, writers and readers don't block each other.
+	if _, err = db.handler.Exec(`PRAGMA journal_mode = wal;`); err != nil {
+		return errors.Wrap(err, "enable wal")
+	}
+
+	// SQLite has a query planner that uses lifecycle stats to find optimizations.
+	// This restricts the SQLite query planner optimizer to only run if sufficient
+	// information has been gathered over the lifecycle of the connection.
+	if _, err = db.handler.Exec(`PRAGMA analysis_limit = 1000;`); err != nil {
+		return errors.Wrap(err, "analysis_limit")
+	}
+
+	// a single writer is all we need and it avoids "database is locked" errors
+	db.handler.SetMaxOpenConns(1)
+
+	// migrate db
+	if err = db.migrateSQLite(); err != nil {
+		return errors.Wrap(err, "could not migrate db")
+	}
+
+	db.log.Debug().Msgf("opened database: %s", db.DSN)
+
+	return nil
+}
+
+func (db *DB) migrateSQLite() error {
+	var version int
+	if err := db.handler.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return errors.Wrap(err, "failed to query schema version")
+	}
+
+	if version == len(sqliteMigrations) {
+		return nil
+	} else if version > len(sqliteMigrations) {
+		return errors.New("seasonpackarr (version %d) older than schema (version: %d)", len(sqliteMigrations), version)
+	}
+
+	db.log.Info().Msgf("Beginning database schema upgrade from version %d to version: %d", version, len(sqliteMigrations))
+
+	tx, err := db.handler.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if version == 0 {
+		if _, err := tx.Exec(sqliteSchema); err != nil {
+			return errors.Wrap(err, "failed to initialize schema")
+		}
+	} else {
+		for i := version; i < len(sqliteMigrations); i++ {
+			db.log.Info().Msgf("Upgrading Database schema to version: %v", i+1)
+			if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+				return errors.Wrap(err, "failed to execute migration #%v", i)
+			}
+		}
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(sqliteMigrations)))
+	if err != nil {
+		return errors.Wrap(err, "failed to bump schema version")
+	}
+
+	db.log.Info().Msgf("Database schema upgraded to version: %d", len(sqliteMigrations))
+
+	return tx.Commit()
+}
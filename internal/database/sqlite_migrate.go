@@ -0,0 +1,72 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+const sqliteSchema = `
+CREATE TABLE pending_match
+(
+    id                INTEGER PRIMARY KEY,
+    release_name      TEXT NOT NULL,
+    client_ep_path    TEXT NOT NULL,
+    client_ep_size    INTEGER NOT NULL,
+    announced_ep_path TEXT NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX pending_match_release_name_index
+    ON pending_match (release_name);
+
+CREATE TABLE history
+(
+    id             INTEGER PRIMARY KEY,
+    action         TEXT NOT NULL,
+    release_name   TEXT NOT NULL,
+    client         TEXT NOT NULL,
+    status_code    INTEGER NOT NULL,
+    error          TEXT,
+    dry_run        BOOLEAN DEFAULT FALSE,
+    rejections     TEXT DEFAULT '[]' NOT NULL,
+    matches        TEXT DEFAULT '[]' NOT NULL,
+    links          TEXT DEFAULT '[]' NOT NULL,
+    found_episodes INTEGER DEFAULT 0 NOT NULL,
+    total_episodes INTEGER DEFAULT 0 NOT NULL,
+    duration_ms    INTEGER NOT NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX history_release_name_index
+    ON history (release_name);
+
+CREATE INDEX history_created_at_index
+    ON history (created_at);
+`
+
+var sqliteMigrations = []string{
+	"",
+	`
+CREATE TABLE history
+(
+    id             INTEGER PRIMARY KEY,
+    action         TEXT NOT NULL,
+    release_name   TEXT NOT NULL,
+    client         TEXT NOT NULL,
+    status_code    INTEGER NOT NULL,
+    error          TEXT,
+    dry_run        BOOLEAN DEFAULT FALSE,
+    rejections     TEXT DEFAULT '[]' NOT NULL,
+    matches        TEXT DEFAULT '[]' NOT NULL,
+    links          TEXT DEFAULT '[]' NOT NULL,
+    found_episodes INTEGER DEFAULT 0 NOT NULL,
+    total_episodes INTEGER DEFAULT 0 NOT NULL,
+    duration_ms    INTEGER NOT NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX history_release_name_index
+    ON history (release_name);
+
+CREATE INDEX history_created_at_index
+    ON history (created_at);
+	`,
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MigrateSQLite_History(t *testing.T) {
+	cfg := &domain.Config{ConfigPath: t.TempDir(), LogLevel: "ERROR"}
+	log := logger.New(cfg)
+
+	db, err := NewDB(cfg, log)
+	require.NoError(t, err)
+
+	// create a database at schema version 1, which only stored the pending matches
+	handler, err := sql.Open("sqlite", db.DSN)
+	require.NoError(t, err)
+
+	_, err = handler.Exec(`
+CREATE TABLE pending_match
+(
+    id                INTEGER PRIMARY KEY,
+    release_name      TEXT NOT NULL,
+    client_ep_path    TEXT NOT NULL,
+    client_ep_size    INTEGER NOT NULL,
+    announced_ep_path TEXT NOT NULL,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+PRAGMA user_version = 1;
+`)
+	require.NoError(t, err)
+	require.NoError(t, handler.Close())
+
+	require.NoError(t, db.Open())
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewHistoryRepo(log, db)
+
+	h := domain.NewHistory(domain.HistoryActionPack)
+	h.ReleaseName = "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	h.Client = "default"
+	h.DryRun = true
+	h.FoundEpisodes = 8
+	h.TotalEpisodes = 10
+	h.AddMatch(domain.HistoryMatch{Season: 1, Episode: 1, Path: "/data/torrents/ep1.mkv", Size: 1000})
+	h.Finish(domain.StatusSuccessfulMatch, nil)
+	require.NoError(t, repo.Store(context.Background(), h))
+
+	got, err := repo.FindByID(context.Background(), h.ID)
+	require.NoError(t, err)
+	assert.True(t, got.DryRun)
+	assert.Equal(t, 8, got.FoundEpisodes)
+	assert.Equal(t, 10, got.TotalEpisodes)
+	assert.Equal(t, h.Matches, got.Matches)
+}
@@ -4,12 +4,83 @@
 
 package domain
 
+import "time"
+
+const (
+	ClientTypeQbittorrent  = "qbittorrent"
+	ClientTypeDeluge       = "deluge"
+	ClientTypeTransmission = "transmission"
+	ClientTypeRtorrent     = "rtorrent"
+	ClientTypeFilesystem   = "filesystem"
+)
+
+var ClientTypes = []string{
+	ClientTypeQbittorrent,
+	ClientTypeDeluge,
+	ClientTypeTransmission,
+	ClientTypeRtorrent,
+	ClientTypeFilesystem,
+}
+
+const (
+	LinkTypeHardlink = "hardlink"
+	LinkTypeReflink  = "reflink"
+	LinkTypeSymlink  = "symlink"
+	LinkTypeCopy     = "copy"
+)
+
+var LinkTypes = []string{
+	LinkTypeHardlink,
+	LinkTypeReflink,
+	LinkTypeSymlink,
+	LinkTypeCopy,
+}
+
+const (
+	SpecialsIgnore = "ignore"
+	SpecialsMatch  = "match"
+	SpecialsCount  = "count"
+)
+
+var SpecialsPolicies = []string{
+	SpecialsIgnore,
+	SpecialsMatch,
+	SpecialsCount,
+}
+
+// VideoExtensions are the video containers that are matched by default.
+var VideoExtensions = []string{".mkv", ".mp4", ".avi", ".ts", ".m2ts", ".m4v", ".wmv"}
+
+// SidecarExtensions are the files that are known to accompany an episode, e.g. subtitles.
+var SidecarExtensions = []string{".srt", ".ass", ".ssa", ".sub", ".idx", ".vtt", ".nfo"}
+
+type PathMapping struct {
+	RemotePath string `yaml:"remotePath"`
+	LocalPath  string `yaml:"localPath"`
+}
+
+type TorrentFilters struct {
+	IncludeCategories []string `yaml:"includeCategories"`
+	ExcludeCategories []string `yaml:"excludeCategories"`
+	IncludeTags       []string `yaml:"includeTags"`
+	ExcludeTags       []string `yaml:"excludeTags"`
+	IncludeSavePaths  []string `yaml:"includeSavePaths"`
+	ExcludeSavePaths  []string `yaml:"excludeSavePaths"`
+}
+
 type Client struct {
-	Host          string `yaml:"host"`
-	Port          int    `yaml:"port"`
-	Username      string `yaml:"username"`
-	Password      string `yaml:"password"`
-	PreImportPath string `yaml:"preImportPath"`
+	Type          string         `yaml:"type"`
+	Host          string         `yaml:"host"`
+	Port          int            `yaml:"port"`
+	Username      string         `yaml:"username"`
+	Password      string         `yaml:"password"`
+	PreImportPath string         `yaml:"preImportPath"`
+	RPCPath       string         `yaml:"rpcPath"`
+	SCGI          bool           `yaml:"scgi"`
+	Directories   []string       `yaml:"directories"`
+	PathMappings  []PathMapping  `yaml:"pathMappings"`
+	LinkTypes     []string       `yaml:"linkTypes"`
+	Filters       TorrentFilters `yaml:"filters"`
 }
 
 type FuzzyMatching struct {
@@ -17,6 +88,22 @@ type FuzzyMatching struct {
 	SimplifyHdrCompare bool `yaml:"simplifyHdrCompare"`
 }
 
+type Cleanup struct {
+	Interval    time.Duration `yaml:"interval"`
+	GracePeriod time.Duration `yaml:"gracePeriod"`
+}
+
+type Exclusions struct {
+	Samples  bool     `yaml:"samples"`
+	MinSize  int      `yaml:"minSize"`
+	Patterns []string `yaml:"patterns"`
+}
+
+type TorrentHealth struct {
+	RequireComplete bool     `yaml:"requireComplete"`
+	ExcludedStates  []string `yaml:"excludedStates"`
+}
+
 type Notifications struct {
 	NotificationLevel []string `yaml:"notificationLevel"`
 	Discord           string   `yaml:"discord"`
@@ -25,19 +112,28 @@ type Notifications struct {
 }
 
 type Config struct {
-	Version            string
-	ConfigPath         string
-	Host               string             `yaml:"host"`
-	Port               int                `yaml:"port"`
-	Clients            map[string]*Client `yaml:"clients"`
-	LogPath            string             `yaml:"logPath"`
-	LogLevel           string             `yaml:"logLevel"`
-	LogMaxSize         int                `yaml:"logMaxSize"`
-	LogMaxBackups      int                `yaml:"logMaxBackups"`
-	SmartMode          bool               `yaml:"smartMode"`
-	SmartModeThreshold float32            `yaml:"smartModeThreshold"`
-	ParseTorrentFile   bool               `yaml:"parseTorrentFile"`
-	FuzzyMatching      FuzzyMatching      `yaml:"fuzzyMatching"`
-	APIToken           string             `yaml:"apiToken"`
-	Notifications      Notifications      `yaml:"notifications"`
+	Version              string
+	ConfigPath           string
+	Host                 string             `yaml:"host"`
+	Port                 int                `yaml:"port"`
+	Clients              map[string]*Client `yaml:"clients"`
+	LogPath              string             `yaml:"logPath"`
+	LogLevel             string             `yaml:"logLevel"`
+	LogMaxSize           int                `yaml:"logMaxSize"`
+	LogMaxBackups        int                `yaml:"logMaxBackups"`
+	SmartMode            bool               `yaml:"smartMode"`
+	SmartModeThreshold   float32            `yaml:"smartModeThreshold"`
+	AnimeMode            bool               `yaml:"animeMode"`
+	Specials             string             `yaml:"specials"`
+	ParseTorrentFile     bool               `yaml:"parseTorrentFile"`
+	VideoExtensions      []string           `yaml:"videoExtensions"`
+	SidecarExtensions    []string           `yaml:"sidecarExtensions"`
+	Exclusions           Exclusions         `yaml:"exclusions"`
+	TorrentHealth        TorrentHealth      `yaml:"torrentHealth"`
+	MatchTTL             time.Duration      `yaml:"matchTTL"`
+	TransactionalLinking bool               `yaml:"transactionalLinking"`
+	Cleanup              Cleanup            `yaml:"cleanup"`
+	FuzzyMatching        FuzzyMatching      `yaml:"fuzzyMatching"`
+	APIToken             string             `yaml:"apiToken"`
+	Notifications        Notifications      `yaml:"notifications"`
 }
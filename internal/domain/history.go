@@ -0,0 +1,132 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+var ErrHistoryNotFound = errors.Sentinel("history not found")
+
+type HistoryRepo interface {
+	Store(ctx context.Context, h *History) error
+	Find(ctx context.Context, params HistoryQueryParams) (*HistoryList, error)
+	FindByID(ctx context.Context, id int64) (*History, error)
+	FindLinkTargets(ctx context.Context) ([]string, error)
+}
+
+const (
+	HistoryActionPack  = "Pack"
+	HistoryActionParse = "Parse"
+)
+
+// History records the outcome of a single pack or parse request. It's also returned as the response
+// of the request, so it doubles as the decision report.
+type History struct {
+	ID            int64              `json:"id"`
+	Action        string             `json:"action"`
+	ReleaseName   string             `json:"releaseName"`
+	Client        string             `json:"client"`
+	StatusCode    StatusCode         `json:"statusCode"`
+	Status        string             `json:"status"`
+	Error         string             `json:"error,omitempty"`
+	DryRun        bool               `json:"dryRun"`
+	Rejections    []HistoryRejection `json:"rejections,omitempty"`
+	Matches       []HistoryMatch     `json:"matches,omitempty"`
+	Links         []HistoryLink      `json:"links,omitempty"`
+	FoundEpisodes int                `json:"foundEpisodes"`
+	TotalEpisodes int                `json:"totalEpisodes,omitempty"`
+	DurationMs    int64              `json:"durationMs"`
+	CreatedAt     time.Time          `json:"createdAt"`
+}
+
+// HistoryMatch is an episode in the client that matched the request. Hash is the info hash of the
+// torrent the episode belongs to and is only known for pack requests. Episodes is only set for multi
+// episode files and contains every episode they cover.
+type HistoryMatch struct {
+	Season   int    `json:"season"`
+	Episode  int    `json:"episode"`
+	Episodes []int  `json:"episodes,omitempty"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// HistoryRejection is a candidate that was rejected while processing a request. For pack requests the
+// candidate is a torrent in the client, for parse requests it's an episode in the client that was compared
+// to PackFile of the announced torrent.
+type HistoryRejection struct {
+	Candidate    string     `json:"candidate"`
+	PackFile     string     `json:"packFile,omitempty"`
+	StatusCode   StatusCode `json:"statusCode"`
+	Reason       string     `json:"reason"`
+	RejectValueA any        `json:"rejectValueA"`
+	RejectValueB any        `json:"rejectValueB"`
+}
+
+type HistoryLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type,omitempty"`
+}
+
+// HistoryQueryParams filters the history, zero values don't filter.
+type HistoryQueryParams struct {
+	Client     string
+	StatusCode StatusCode
+	Search     string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
+
+// HistoryList is a page of history entries. The entries only contain the summary of each request,
+// the rejections, matches and links are returned by HistoryRepo.FindByID.
+type HistoryList struct {
+	Data       []History `json:"data"`
+	TotalCount int       `json:"totalCount"`
+}
+
+func NewHistory(action string) *History {
+	return &History{
+		Action:     action,
+		Rejections: make([]HistoryRejection, 0),
+		Matches:    make([]HistoryMatch, 0),
+		Links:      make([]HistoryLink, 0),
+		CreatedAt:  time.Now(),
+	}
+}
+
+func (h *History) AddRejection(candidate string, packFile string, info CompareInfo) {
+	h.Rejections = append(h.Rejections, HistoryRejection{
+		Candidate:    candidate,
+		PackFile:     packFile,
+		StatusCode:   info.StatusCode,
+		Reason:       info.StatusCode.String(),
+		RejectValueA: info.RejectValueA,
+		RejectValueB: info.RejectValueB,
+	})
+}
+
+func (h *History) AddMatch(m HistoryMatch) {
+	h.Matches = append(h.Matches, m)
+}
+
+func (h *History) AddLink(source string, target string, linkType string) {
+	h.Links = append(h.Links, HistoryLink{Source: source, Target: target, Type: linkType})
+}
+
+// Finish sets the outcome of the request.
+func (h *History) Finish(code StatusCode, err error) {
+	h.StatusCode = code
+	h.Status = code.String()
+	if err != nil {
+		h.Error = err.Error()
+	}
+	h.DurationMs = time.Since(h.CreatedAt).Milliseconds()
+}
@@ -17,6 +17,7 @@ const (
 	StatusRepackStatusMismatch     StatusCode = 206
 	StatusHdrMismatch              StatusCode = 207
 	StatusStreamingServiceMismatch StatusCode = 208
+	StatusIncompleteTorrent        StatusCode = 209
 	StatusAlreadyInClient          StatusCode = 210
 	StatusNotASeasonPack           StatusCode = 211
 	StatusSizeMismatch             StatusCode = 212
@@ -25,6 +26,7 @@ const (
 	StatusBelowThreshold           StatusCode = 230
 	StatusSuccessfulMatch          StatusCode = 250
 	StatusSuccessfulHardlink       StatusCode = 250
+	StatusDryRunHardlink           StatusCode = 251
 	StatusFailedHardlink           StatusCode = 440
 	StatusFailedMatchToTorrentEps  StatusCode = 445
 	StatusClientNotFound           StatusCode = 472
@@ -59,6 +61,8 @@ func (s StatusCode) String() string {
 		return "HDR metadata did not match"
 	case StatusStreamingServiceMismatch:
 		return "streaming service did not match"
+	case StatusIncompleteTorrent:
+		return "torrent in client is incomplete or errored"
 	case StatusAlreadyInClient:
 		return "release already in client"
 	case StatusNotASeasonPack:
@@ -73,6 +77,8 @@ func (s StatusCode) String() string {
 		return "number of matches below threshold"
 	case StatusSuccessfulMatch:
 		return "successful match"
+	case StatusDryRunHardlink:
+		return "dry run, hardlinks would have been created"
 	case StatusFailedHardlink:
 		return "could not create hardlinks"
 	case StatusFailedMatchToTorrentEps:
@@ -124,6 +130,7 @@ var NotificationStatusMap = map[string][]StatusCode{
 		StatusRepackStatusMismatch,
 		StatusHdrMismatch,
 		StatusStreamingServiceMismatch,
+		StatusIncompleteTorrent,
 		StatusAlreadyInClient,
 		StatusNotASeasonPack,
 		StatusBelowThreshold,
@@ -0,0 +1,29 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type MatchRepo interface {
+	Store(ctx context.Context, releaseName string, matches []Match) error
+	FindAll(ctx context.Context) ([]PendingMatch, error)
+	Delete(ctx context.Context, releaseName string) error
+	DeleteOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
+
+type Match struct {
+	ClientEpPath    string
+	ClientEpSize    int64
+	AnnouncedEpPath string
+}
+
+// PendingMatch holds the matches of a pack request until the parse request arrives.
+type PendingMatch struct {
+	ReleaseName string
+	Matches     []Match
+	CreatedAt   time.Time
+}
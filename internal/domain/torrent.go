@@ -0,0 +1,28 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+// TorrentState is the client agnostic state of a torrent.
+type TorrentState string
+
+const (
+	TorrentStateDownloading TorrentState = "downloading"
+	TorrentStateSeeding     TorrentState = "seeding"
+	TorrentStatePaused      TorrentState = "paused"
+	TorrentStateQueued      TorrentState = "queued"
+	TorrentStateChecking    TorrentState = "checking"
+	TorrentStateError       TorrentState = "error"
+	TorrentStateUnknown     TorrentState = "unknown"
+)
+
+// TorrentStates contains every state a torrent can be in.
+var TorrentStates = []TorrentState{
+	TorrentStateDownloading,
+	TorrentStateSeeding,
+	TorrentStatePaused,
+	TorrentStateQueued,
+	TorrentStateChecking,
+	TorrentStateError,
+	TorrentStateUnknown,
+}
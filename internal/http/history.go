@@ -0,0 +1,137 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+const (
+	historyDefaultLimit = 50
+	historyMaxLimit     = 500
+)
+
+type historyHandler struct {
+	log         zerolog.Logger
+	historyRepo domain.HistoryRepo
+}
+
+func newHistoryHandler(log logger.Logger, historyRepo domain.HistoryRepo) *historyHandler {
+	return &historyHandler{
+		log:         log.With().Str("module", "history").Logger(),
+		historyRepo: historyRepo,
+	}
+}
+
+func (h *historyHandler) Routes(r *gin.RouterGroup) {
+	r.GET("", h.find)
+	r.GET("/:id", h.findByID)
+}
+
+func (h *historyHandler) find(c *gin.Context) {
+	params, err := parseHistoryQueryParams(c)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := h.historyRepo.Find(c.Request.Context(), params)
+	if err != nil {
+		h.log.Error().Err(err).Msg("error finding history")
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *historyHandler) findByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, errors.New("invalid id: %s", c.Param("id")))
+		return
+	}
+
+	history, err := h.historyRepo.FindByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrHistoryNotFound) {
+			writeError(c, http.StatusNotFound, err)
+			return
+		}
+
+		h.log.Error().Err(err).Msgf("error finding history: %d", id)
+		writeError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// parseHistoryQueryParams reads the filters and pagination from the query string. Times are expected
+// in RFC 3339 format, e.g. 2024-01-02T15:04:05Z.
+func parseHistoryQueryParams(c *gin.Context) (domain.HistoryQueryParams, error) {
+	params := domain.HistoryQueryParams{
+		Client: c.Query("client"),
+		Search: c.Query("q"),
+		Limit:  historyDefaultLimit,
+	}
+
+	if v := c.Query("statusCode"); v != "" {
+		code, err := strconv.Atoi(v)
+		if err != nil {
+			return params, errors.New("invalid statusCode: %s", v)
+		}
+		params.StatusCode = domain.StatusCode(code)
+	}
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return params, errors.New("invalid from: %s", v)
+		}
+		params.From = t
+	}
+
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return params, errors.New("invalid to: %s", v)
+		}
+		params.To = t
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return params, errors.New("invalid limit: %s", v)
+		}
+		params.Limit = min(limit, historyMaxLimit)
+	}
+
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return params, errors.New("invalid offset: %s", v)
+		}
+		params.Offset = offset
+	}
+
+	return params, nil
+}
+
+func writeError(c *gin.Context, code int, err error) {
+	c.AbortWithStatusJSON(code, gin.H{
+		"statusCode": code,
+		"error":      err.Error(),
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/config"
+	"github.com/nuxencs/seasonpackarr/internal/database"
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HistoryHandler(t *testing.T) {
+	cfg := &config.AppConfig{Config: &domain.Config{
+		Version:  "dev",
+		LogLevel: "ERROR",
+		APIToken: "token",
+	}}
+	log := logger.New(cfg.Config)
+
+	db, err := database.NewDB(&domain.Config{ConfigPath: t.TempDir()}, log)
+	require.NoError(t, err)
+	require.NoError(t, db.Open())
+	t.Cleanup(func() { db.Close() })
+
+	historyRepo := database.NewHistoryRepo(log, db)
+
+	for _, client := range []string{"default", "secondary"} {
+		h := domain.NewHistory(domain.HistoryActionPack)
+		h.ReleaseName = "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+		h.Client = client
+		h.AddMatch(domain.HistoryMatch{Season: 1, Episode: 1, Path: "/data/torrents/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv"})
+		h.Finish(domain.StatusSuccessfulHardlink, nil)
+		require.NoError(t, historyRepo.Store(context.Background(), h))
+	}
+
+	handler := NewServer(log, cfg, nil, database.NewMatchRepo(log, db), historyRepo).Handler()
+
+	tests := []struct {
+		name     string
+		url      string
+		token    string
+		wantCode int
+	}{
+		{name: "unauthorized", url: "/api/history", wantCode: http.StatusUnauthorized},
+		{name: "list", url: "/api/history?client=secondary", token: "token", wantCode: http.StatusOK},
+		{name: "invalid_param", url: "/api/history?from=yesterday", token: "token", wantCode: http.StatusBadRequest},
+		{name: "by_id", url: "/api/history/1", token: "token", wantCode: http.StatusOK},
+		{name: "not_found", url: "/api/history/42", token: "token", wantCode: http.StatusNotFound},
+		{name: "invalid_id", url: "/api/history/abc", token: "token", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("X-API-Token", tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			switch tt.name {
+			case "list":
+				var list domain.HistoryList
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+				assert.Equal(t, 1, list.TotalCount)
+				require.Len(t, list.Data, 1)
+				assert.Equal(t, "secondary", list.Data[0].Client)
+
+			case "by_id":
+				var h domain.History
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &h))
+				assert.Equal(t, int64(1), h.ID)
+				assert.Len(t, h.Matches, 1)
+			}
+		})
+	}
+}
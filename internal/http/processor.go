@@ -4,12 +4,17 @@
 package http
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/nuxencs/seasonpackarr/internal/clients"
 	"github.com/nuxencs/seasonpackarr/internal/config"
 	"github.com/nuxencs/seasonpackarr/internal/domain"
 	"github.com/nuxencs/seasonpackarr/internal/logger"
@@ -18,73 +23,245 @@ import (
 	"github.com/nuxencs/seasonpackarr/internal/utils"
 	"github.com/nuxencs/seasonpackarr/pkg/errors"
 
-	"github.com/autobrr/go-qbittorrent"
 	"github.com/gin-gonic/gin"
-	"github.com/moistari/rls"
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/rs/zerolog"
 )
 
 type processor struct {
-	log  zerolog.Logger
-	cfg  *config.AppConfig
-	noti domain.Sender
-	req  *request
+	log         zerolog.Logger
+	cfg         *config.AppConfig
+	noti        domain.Sender
+	matchRepo   domain.MatchRepo
+	historyRepo domain.HistoryRepo
+	req         *request
+	history     *domain.History
 }
 
 type request struct {
 	Name       string
 	Torrent    json.RawMessage
-	Client     *qbittorrent.Client
+	Client     clients.TorrentClient
 	ClientName string
+	DryRun     bool
 }
 
 type entry struct {
-	t qbittorrent.Torrent
-	r rls.Release
+	t clients.Torrent
+	r release.Pack
+}
+
+// episodeFile is a video of a client torrent together with the episode it contains.
+type episodeFile struct {
+	f clients.File
+	r release.Pack
 }
 
 type torrentRlsEntries struct {
 	entriesMap  map[string][]entry
-	rlsMap      map[string]rls.Release
+	rlsMap      map[string]release.Pack
 	lastUpdated time.Time
 	err         error
 	sync.Mutex
 }
 
-type matchInfo struct {
-	clientEpPath    string
-	clientEpSize    int64
-	announcedEpPath string
+type pendingMatches struct {
+	matches   []domain.Match
+	createdAt time.Time
 }
 
+// expired reports whether the matches are older than ttl. A ttl of 0 never expires.
+func (pm pendingMatches) expired(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && now.Sub(pm.createdAt) > ttl
+}
+
+const matchSweepInterval = time.Minute
+
 var (
-	clientMap  = xsync.NewMapOf[string, *qbittorrent.Client]()
-	matchesMap = xsync.NewMapOf[string, []matchInfo]()
+	clientMap  = xsync.NewMapOf[string, clients.TorrentClient]()
+	matchesMap = xsync.NewMapOf[string, pendingMatches]()
 	torrentMap = xsync.NewMapOf[string, *torrentRlsEntries]()
+
+	// dryRunMatchesMap holds the matches of dry run requests, they're never persisted and don't touch matchesMap.
+	dryRunMatchesMap = xsync.NewMapOf[string, pendingMatches]()
 )
 
-func newProcessor(log logger.Logger, config *config.AppConfig, notification domain.Sender) *processor {
+func newProcessor(log logger.Logger, config *config.AppConfig, notification domain.Sender, matchRepo domain.MatchRepo,
+	historyRepo domain.HistoryRepo) *processor {
 	return &processor{
-		log:  log.With().Str("module", "processor").Logger(),
-		cfg:  config,
-		noti: notification,
+		log:         log.With().Str("module", "processor").Logger(),
+		cfg:         config,
+		noti:        notification,
+		matchRepo:   matchRepo,
+		historyRepo: historyRepo,
+		history:     domain.NewHistory(""),
+	}
+}
+
+// loadMatches restores the pending matches that were stored before the last shutdown.
+func loadMatches(ctx context.Context, matchRepo domain.MatchRepo) (int, error) {
+	pending, err := matchRepo.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pm := range pending {
+		matchesMap.Store(pm.ReleaseName, pendingMatches{matches: pm.Matches, createdAt: pm.CreatedAt})
+	}
+
+	return len(pending), nil
+}
+
+// matchStats are the sizes of the in memory caches, they are logged so leaks are noticed on long-running instances.
+type matchStats struct {
+	pending int
+	cached  int
+}
+
+// sweepMatches removes the pending matches that are older than ttl from memory and the database, and returns the
+// sizes of the caches afterwards.
+func sweepMatches(ctx context.Context, log zerolog.Logger, matchRepo domain.MatchRepo, ttl time.Duration) matchStats {
+	if ttl > 0 {
+		now := time.Now()
+		expired := 0
+
+		matchesMap.Range(func(name string, pm pendingMatches) bool {
+			if pm.expired(ttl, now) {
+				matchesMap.Delete(name)
+				expired++
+			}
+			return true
+		})
+		dryRunMatchesMap.Range(func(name string, pm pendingMatches) bool {
+			if pm.expired(ttl, now) {
+				dryRunMatchesMap.Delete(name)
+			}
+			return true
+		})
+
+		if _, err := matchRepo.DeleteOlderThan(ctx, now.Add(-ttl)); err != nil {
+			log.Error().Err(err).Msg("error deleting expired pending matches")
+		}
+
+		if expired > 0 {
+			log.Debug().Msgf("removed %d expired pending matches", expired)
+		}
+	}
+
+	stats := matchStats{pending: matchesMap.Size()}
+	torrentMap.Range(func(clientName string, tre *torrentRlsEntries) bool {
+		log.Trace().Msgf("cached releases for client %s: %d", clientName, len(tre.rlsMap))
+		stats.cached += len(tre.rlsMap)
+		return true
+	})
+
+	return stats
+}
+
+// runMatchSweeper calls sweepMatches periodically until ctx is cancelled. The sizes of the caches are logged
+// whenever they changed, so they're visible without flooding the log.
+func runMatchSweeper(ctx context.Context, log zerolog.Logger, cfg *config.AppConfig, matchRepo domain.MatchRepo) {
+	ticker := time.NewTicker(matchSweepInterval)
+	defer ticker.Stop()
+
+	var last matchStats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := sweepMatches(ctx, log, matchRepo, cfg.Config.MatchTTL)
+			if stats != last {
+				log.Info().Msgf("pending matches: %d, cached releases: %d", stats.pending, stats.cached)
+				last = stats
+			}
+		}
+	}
+}
+
+func (p *processor) storeMatches(ctx context.Context, name string, matches []domain.Match) {
+	if p.req.DryRun {
+		dryRunMatchesMap.Store(name, pendingMatches{matches: matches, createdAt: time.Now()})
+		return
+	}
+
+	matchesMap.Store(name, pendingMatches{matches: matches, createdAt: time.Now()})
+
+	if err := p.matchRepo.Store(ctx, name, matches); err != nil {
+		p.log.Error().Err(err).Msgf("error persisting matches: %s", name)
+	}
+}
+
+func (p *processor) deleteMatches(ctx context.Context, name string) {
+	if p.req.DryRun {
+		dryRunMatchesMap.Delete(name)
+		return
+	}
+
+	matchesMap.Delete(name)
+
+	if err := p.matchRepo.Delete(ctx, name); err != nil {
+		p.log.Error().Err(err).Msgf("error deleting persisted matches: %s", name)
+	}
+}
+
+// findMatches returns the pending matches of name. Dry runs prefer the matches of an earlier dry run and fall back
+// to the real ones, which are only read.
+func (p *processor) findMatches(name string) (pendingMatches, bool) {
+	if p.req.DryRun {
+		if pending, ok := dryRunMatchesMap.Load(name); ok {
+			return pending, true
+		}
+	}
+
+	return matchesMap.Load(name)
+}
+
+// storeHistory records the outcome of the current request, errors are only logged so the request itself
+// isn't affected by them.
+func (p *processor) storeHistory(ctx context.Context, statusCode domain.StatusCode, err error) {
+	if p.req != nil {
+		p.history.ReleaseName = p.req.Name
+		p.history.Client = p.req.ClientName
+	}
+	p.history.DryRun = p.req != nil && p.req.DryRun
+	p.history.Finish(statusCode, err)
+
+	if storeErr := p.historyRepo.Store(context.WithoutCancel(ctx), p.history); storeErr != nil {
+		p.log.Error().Err(storeErr).Msgf("error storing history: %s", p.history.ReleaseName)
+	}
+}
+
+// linkTypes returns the link types of the client in the order they should be tried.
+func linkTypes(client *domain.Client) []string {
+	if len(client.LinkTypes) == 0 {
+		return []string{domain.LinkTypeHardlink}
+	}
+
+	return client.LinkTypes
+}
+
+// multiEpisodes returns the episodes of a multi episode release, or nil for single episode releases.
+func multiEpisodes(r release.Pack) []int {
+	if !r.MultiEpisode() {
+		return nil
 	}
+
+	return r.Episodes
 }
 
-func (p *processor) getClient(client *domain.Client, clientName string) error {
+func (p *processor) getClient(ctx context.Context, client *domain.Client, clientName string) error {
 	c, ok := clientMap.Load(clientName)
 	if !ok {
-		clientCfg := qbittorrent.Config{
-			Host:     fmt.Sprintf("http://%s:%d", client.Host, client.Port),
-			Username: client.Username,
-			Password: client.Password,
-		}
+		var err error
 
-		c = qbittorrent.NewClient(clientCfg)
+		c, err = clients.New(client, p.videoExtensions(), p.cfg.Config.SidecarExtensions)
+		if err != nil {
+			return err
+		}
 
-		if err := c.Login(); err != nil {
-			return errors.Wrap(err, "failed to login to qbittorrent")
+		if err = c.Login(ctx); err != nil {
+			return err
 		}
 
 		clientMap.Store(clientName, c)
@@ -94,14 +271,14 @@ func (p *processor) getClient(client *domain.Client, clientName string) error {
 	return nil
 }
 
-func (p *processor) getAllTorrents(clientName string) torrentRlsEntries {
+func (p *processor) getAllTorrents(ctx context.Context, clientName string) *torrentRlsEntries {
 	f := func() *torrentRlsEntries {
 		tre, ok := torrentMap.Load(clientName)
 		if ok {
 			return tre
 		}
 
-		entries := &torrentRlsEntries{rlsMap: make(map[string]rls.Release)}
+		entries := &torrentRlsEntries{rlsMap: make(map[string]release.Pack)}
 		torrentMap.Store(clientName, entries)
 		return entries
 	}
@@ -109,7 +286,7 @@ func (p *processor) getAllTorrents(clientName string) torrentRlsEntries {
 	entries := f()
 	cur := time.Now()
 	if entries.lastUpdated.After(cur) {
-		return *entries
+		return entries
 	}
 
 	entries.Lock()
@@ -117,34 +294,44 @@ func (p *processor) getAllTorrents(clientName string) torrentRlsEntries {
 
 	entries = f()
 	if entries.lastUpdated.After(cur) {
-		return *entries
+		return entries
 	}
 
-	ts, err := p.req.Client.GetTorrents(qbittorrent.TorrentFilterOptions{})
+	ts, err := p.req.Client.GetTorrents(ctx)
 	if err != nil {
-		return torrentRlsEntries{err: err}
+		return &torrentRlsEntries{err: err}
 	}
 
 	after := time.Now()
-	entries = &torrentRlsEntries{entriesMap: make(map[string][]entry), lastUpdated: after.Add(after.Sub(cur)), rlsMap: entries.rlsMap}
+	prevRlsMap := entries.rlsMap
+	// only keep the parsed releases of torrents that are still in the client, so the cache can't grow unbounded
+	entries = &torrentRlsEntries{entriesMap: make(map[string][]entry), lastUpdated: after.Add(after.Sub(cur)), rlsMap: make(map[string]release.Pack, len(ts))}
+
+	clientCfg := p.cfg.Config.Clients[clientName]
 
 	for _, t := range ts {
-		r, ok := entries.rlsMap[t.Name]
+		// torrents that don't pass the filters of the client are never candidates
+		if clientCfg != nil && !clients.MatchesFilters(t, clientCfg.Filters) {
+			continue
+		}
+
+		r, ok := prevRlsMap[t.Name]
 		if !ok {
-			r = rls.ParseString(t.Name)
-			entries.rlsMap[t.Name] = r
+			r = release.ParsePack(t.Name)
 		}
+		entries.rlsMap[t.Name] = r
 
-		fmtTitle := utils.GetFormattedTitle(r)
+		// entries are grouped by show, so multi season packs can find the episodes of all their seasons
+		fmtTitle := utils.GetFormattedShowTitle(r.Release)
 		entries.entriesMap[fmtTitle] = append(entries.entriesMap[fmtTitle], entry{t: t, r: r})
 	}
 
 	torrentMap.Store(clientName, entries)
-	return *entries
+	return entries
 }
 
-func (p *processor) getFiles(hash string) (*qbittorrent.TorrentFiles, error) {
-	return p.req.Client.GetFilesInformation(hash)
+func (p *processor) getFiles(ctx context.Context, hash string) ([]clients.File, error) {
+	return p.req.Client.GetFiles(ctx, hash)
 }
 
 func (p *processor) getClientName() string {
@@ -161,16 +348,28 @@ func (p *processor) getClientName() string {
 func (p *processor) ProcessSeasonPackHandler(c *gin.Context) {
 	p.log.Info().Msg("starting to process season pack request")
 
+	p.history.Action = domain.HistoryActionPack
+
 	if err := json.NewDecoder(c.Request.Body).Decode(&p.req); err != nil {
 		p.log.Error().Err(err).Msgf("%s", domain.StatusDecodingError)
-		c.AbortWithStatusJSON(domain.StatusDecodingError.Code(), gin.H{
-			"statusCode": domain.StatusDecodingError.Code(),
-			"error":      err.Error(),
-		})
+		p.storeHistory(c.Request.Context(), domain.StatusDecodingError, err)
+		c.AbortWithStatusJSON(domain.StatusDecodingError.Code(), p.history)
+		return
+	}
+
+	if dryRun, _ := strconv.ParseBool(c.Query("dryRun")); dryRun {
+		p.req.DryRun = true
+	}
+
+	statusCode, err := p.processSeasonPack(c.Request.Context())
+	p.storeHistory(c.Request.Context(), statusCode, err)
+
+	if p.req.DryRun {
+		p.log.Info().Msg("finished dry run, no hardlinks were created")
+		c.JSON(statusCode.Code(), p.history)
 		return
 	}
 
-	statusCode, err := p.processSeasonPack()
 	if err != nil {
 		go func() {
 			if sendErr := p.noti.Send(statusCode, domain.NotificationPayload{
@@ -184,10 +383,7 @@ func (p *processor) ProcessSeasonPackHandler(c *gin.Context) {
 		}()
 
 		p.log.Error().Err(err).Msg("error processing season pack")
-		c.AbortWithStatusJSON(statusCode.Code(), gin.H{
-			"statusCode": statusCode.Code(),
-			"error":      err.Error(),
-		})
+		c.AbortWithStatusJSON(statusCode.Code(), p.history)
 		return
 	}
 
@@ -202,10 +398,10 @@ func (p *processor) ProcessSeasonPackHandler(c *gin.Context) {
 	}()
 
 	p.log.Info().Msg("successfully matched season pack to episodes in client")
-	c.String(statusCode.Code(), statusCode.String())
+	c.JSON(statusCode.Code(), p.history)
 }
 
-func (p *processor) processSeasonPack() (domain.StatusCode, error) {
+func (p *processor) processSeasonPack(ctx context.Context) (domain.StatusCode, error) {
 	clientName := p.getClientName()
 
 	p.log.UpdateContext(func(c zerolog.Context) zerolog.Context {
@@ -216,24 +412,43 @@ func (p *processor) processSeasonPack() (domain.StatusCode, error) {
 	if !ok {
 		return domain.StatusClientNotFound, domain.StatusClientNotFound.Error()
 	}
-	p.log.Info().Msgf("using %s client serving at %s:%d", clientName, clientCfg.Host, clientCfg.Port)
+	p.log.Info().Msgf("using %s client of type %s serving at %s:%d", clientName, clientCfg.Type, clientCfg.Host, clientCfg.Port)
 
 	if len(p.req.Name) == 0 {
 		return domain.StatusAnnounceNameError, domain.StatusAnnounceNameError.Error()
 	}
 
-	if err := p.getClient(clientCfg, clientName); err != nil {
+	if err := p.getClient(ctx, clientCfg, clientName); err != nil {
 		return domain.StatusGetClientError, errors.Wrap(err, domain.StatusGetClientError.String())
 	}
 
-	tre := p.getAllTorrents(clientName)
+	tre := p.getAllTorrents(ctx, clientName)
 	if tre.err != nil {
 		return domain.StatusGetTorrentsError, errors.Wrap(tre.err, domain.StatusGetTorrentsError.String())
 	}
 
-	requestRls := rls.ParseString(p.req.Name)
-	clientEntries, ok := tre.entriesMap[utils.GetFormattedTitle(requestRls)]
-	if !ok {
+	requestPack := release.ParsePack(p.req.Name)
+	requestRls := requestPack.Release
+	if requestPack.MultiSeason() {
+		p.log.Debug().Msgf("multi season pack: seasons(%v), complete(%t)", requestPack.Seasons, requestPack.Complete)
+	}
+
+	showEntries := tre.entriesMap[utils.GetFormattedShowTitle(requestRls)]
+
+	showRls := make([]release.Pack, 0, len(showEntries))
+	for _, e := range showEntries {
+		showRls = append(showRls, e.r)
+	}
+	numbers := p.getEpisodeNumbers(requestRls.Title, showRls)
+
+	clientEntries := make([]entry, 0)
+	for _, e := range showEntries {
+		e.r = e.r.Resolve(numbers, 0)
+		if p.includes(requestPack, e.r) {
+			clientEntries = append(clientEntries, e)
+		}
+	}
+	if len(clientEntries) == 0 {
 		return domain.StatusNoMatches, domain.StatusNoMatches.Error()
 	}
 
@@ -241,18 +456,21 @@ func (p *processor) processSeasonPack() (domain.StatusCode, error) {
 	p.log.Debug().Msgf("formatted season pack name: %s", announcedPackName)
 
 	for _, clientEntry := range clientEntries {
-		switch compareInfo := release.CheckCandidates(requestRls, clientEntry.r, p.cfg.Config.FuzzyMatching); compareInfo.StatusCode {
+		switch compareInfo := release.CheckCandidates(requestRls, clientEntry.r.Release, p.cfg.Config.FuzzyMatching); compareInfo.StatusCode {
 		case domain.StatusAlreadyInClient, domain.StatusNotASeasonPack:
+			p.history.AddRejection(clientEntry.t.Name, "", compareInfo)
 			return compareInfo.StatusCode, compareInfo.StatusCode.Error()
 		}
 	}
 
+	exclusions := p.exclusions()
+
 	codeSet := make(map[domain.StatusCode]bool)
-	epsSet := make(map[int]struct{})
-	matches := make([]matchInfo, 0, len(clientEntries))
+	epsPerSeason := make(map[int]map[int]struct{})
+	matches := make([]domain.Match, 0, len(clientEntries))
 
 	for _, clientEntry := range clientEntries {
-		switch compareInfo := release.CheckCandidates(requestRls, clientEntry.r, p.cfg.Config.FuzzyMatching); compareInfo.StatusCode {
+		switch compareInfo := release.CheckCandidates(requestRls, clientEntry.r.Release, p.cfg.Config.FuzzyMatching); compareInfo.StatusCode {
 		case domain.StatusAlreadyInClient, domain.StatusNotASeasonPack:
 			return compareInfo.StatusCode, compareInfo.StatusCode.Error()
 
@@ -262,75 +480,111 @@ func (p *processor) processSeasonPack() (domain.StatusCode, error) {
 			p.log.Info().Msgf("%s: request(%s => %v), client(%s => %v)",
 				compareInfo.StatusCode, requestRls.String(), compareInfo.RejectValueA,
 				clientEntry.r.String(), compareInfo.RejectValueB)
+			p.history.AddRejection(clientEntry.t.Name, "", compareInfo)
 			codeSet[compareInfo.StatusCode] = true
 			continue
 
 		case domain.StatusSuccessfulMatch:
-			torrentFiles, err := p.getFiles(clientEntry.t.Hash)
+			// files of incomplete torrents might not be fully written yet, so they are neither counted nor linked
+			if !p.healthy(clientEntry.t) {
+				info := domain.CompareInfo{
+					StatusCode:   domain.StatusIncompleteTorrent,
+					RejectValueA: clientEntry.t.State,
+					RejectValueB: fmt.Sprintf("%.2f%%", clientEntry.t.Progress*100),
+				}
+				p.log.Info().Msgf("%s: client(%s => state %s, progress %v)", info.StatusCode,
+					clientEntry.t.Name, info.RejectValueA, info.RejectValueB)
+				p.history.AddRejection(clientEntry.t.Name, "", info)
+				codeSet[info.StatusCode] = true
+				continue
+			}
+
+			torrentFiles, err := p.getFiles(ctx, clientEntry.t.Hash)
 			if err != nil {
 				p.log.Error().Err(err).Msgf("error getting files: %s", clientEntry.t.Name)
 				continue
 			}
 
-			var fileName = ""
-			var size int64 = 0
-			for _, f := range *torrentFiles {
-				if filepath.Ext(f.Name) != ".mkv" {
-					continue
-				}
-
-				fileName = f.Name
-				size = f.Size
-				break
-			}
-			if len(fileName) == 0 || size == 0 {
-				p.log.Error().Err(err).Msgf("error getting filename or size: %s", clientEntry.t.Name)
+			epFiles := p.episodeFiles(requestPack, clientEntry, torrentFiles, exclusions, numbers)
+			if len(epFiles) == 0 {
+				p.log.Error().Msgf("error getting episode files: %s", clientEntry.t.Name)
 				continue
 			}
 
-			epRls := rls.ParseString(clientEntry.t.Name)
-			clientEpPath := filepath.Join(clientEntry.t.SavePath, fileName)
-			announcedEpPath := filepath.Join(clientCfg.PreImportPath, announcedPackName, filepath.Base(fileName))
+			for _, epFile := range epFiles {
+				epRls := epFile.r
+				clientEpPath := filepath.Join(clientEntry.t.SavePath, epFile.f.Path)
+				announcedEpPath := filepath.Join(clientCfg.PreImportPath, announcedPackName, filepath.Base(epFile.f.Path))
 
-			epsSet[epRls.Episode] = struct{}{}
+				if epsPerSeason[epRls.Series] == nil {
+					epsPerSeason[epRls.Series] = make(map[int]struct{})
+				}
+				// multi episode files count towards every episode they cover
+				for _, ep := range epRls.Episodes {
+					epsPerSeason[epRls.Series][ep] = struct{}{}
+				}
+				p.history.AddMatch(domain.HistoryMatch{
+					Season:   epRls.Series,
+					Episode:  epRls.Episode,
+					Episodes: multiEpisodes(epRls),
+					Path:     clientEpPath,
+					Size:     epFile.f.Size,
+					Hash:     clientEntry.t.Hash,
+				})
+
+				// append current match to matches slice
+				matches = append(matches, domain.Match{
+					ClientEpPath:    clientEpPath,
+					ClientEpSize:    epFile.f.Size,
+					AnnouncedEpPath: announcedEpPath,
+				})
+			}
 
-			// append current matchInfo to matches slice
-			matches = append(matches, matchInfo{
-				clientEpPath:    clientEpPath,
-				clientEpSize:    size,
-				announcedEpPath: announcedEpPath,
-			})
+			// sidecars are only kept so they can be linked once the torrent shows that the pack contains them
+			for _, f := range torrentFiles {
+				if !utils.HasExtension(f.Path, p.cfg.Config.SidecarExtensions) {
+					continue
+				}
 
-			p.log.Debug().Msgf("matched torrent from client: name(%s), size(%d), hash(%s)",
-				clientEntry.t.Name, size, clientEntry.t.Hash)
+				matches = append(matches, domain.Match{
+					ClientEpPath:    filepath.Join(clientEntry.t.SavePath, f.Path),
+					ClientEpSize:    f.Size,
+					AnnouncedEpPath: filepath.Join(clientCfg.PreImportPath, announcedPackName, filepath.Base(f.Path)),
+				})
+			}
+
+			p.log.Debug().Msgf("matched torrent from client: name(%s), files(%d), hash(%s)",
+				clientEntry.t.Name, len(epFiles), clientEntry.t.Hash)
 			codeSet[compareInfo.StatusCode] = true
 			continue
 		}
 	}
 
+	for season, eps := range epsPerSeason {
+		if season == 0 && !p.countSpecials(requestPack) {
+			continue
+		}
+		p.history.FoundEpisodes += len(eps)
+	}
+
 	if !codeSet[domain.StatusSuccessfulMatch] {
+		// the episodes would have matched if their torrents were complete
+		if codeSet[domain.StatusIncompleteTorrent] {
+			return domain.StatusIncompleteTorrent, domain.StatusIncompleteTorrent.Error()
+		}
 		return domain.StatusNoMatches, domain.StatusNoMatches.Error()
 	}
 
 	// dedupe matches and store in matchesMap
 	matches = utils.DedupeSlice(matches)
-	matchesMap.Store(p.req.Name, matches)
+	p.storeMatches(ctx, p.req.Name, matches)
 
 	if p.cfg.Config.SmartMode {
-		totalEps, err := utils.GetEpisodesPerSeason(requestRls.Title, requestRls.Series)
-		if err != nil {
-			return domain.StatusEpisodeCountError, errors.Wrap(err, domain.StatusEpisodeCountError.String())
-		}
-
-		foundEps := len(epsSet)
-		percentEps := release.PercentOfTotalEpisodes(totalEps, foundEps)
-
-		if percentEps < p.cfg.Config.SmartModeThreshold {
+		if statusCode, err := p.checkSmartModeThreshold(requestPack, epsPerSeason); err != nil {
 			// delete match from matchesMap if threshold is not met
-			matchesMap.Delete(p.req.Name)
+			p.deleteMatches(ctx, p.req.Name)
 
-			return domain.StatusBelowThreshold, errors.Wrap(fmt.Errorf("found %d/%d (%.2f%%) episodes in client",
-				foundEps, totalEps, percentEps*100), domain.StatusBelowThreshold.String())
+			return statusCode, err
 		}
 	}
 
@@ -339,36 +593,324 @@ func (p *processor) processSeasonPack() (domain.StatusCode, error) {
 	}
 
 	successfulHardlink := false
+	var linkErr error
+
+	tx := utils.NewLinkTransaction(linkTypes(clientCfg))
 
 	for _, match := range matches {
-		if err := utils.CreateHardlink(match.clientEpPath, match.announcedEpPath); err != nil {
-			p.log.Error().Err(err).Msgf("error creating hardlink: %s", match.clientEpPath)
+		// without the torrent it's unknown if the pack contains the sidecar, so it's not linked
+		if p.isSidecar(match.ClientEpPath) {
+			p.log.Debug().Msgf("skipping sidecar, it's only linked if present in the parsed torrent: %s", match.ClientEpPath)
+			continue
+		}
+
+		// without the torrent it's unknown if the pack contains the special, so it's not linked
+		if !requestPack.HasSeason(0) && release.ParsePack(filepath.Base(match.ClientEpPath)).Special() {
+			p.log.Debug().Msgf("skipping special, it's only linked if present in the parsed torrent: %s", match.ClientEpPath)
+			continue
+		}
+
+		if p.req.DryRun {
+			p.history.AddLink(match.ClientEpPath, match.AnnouncedEpPath, linkTypes(clientCfg)[0])
+			successfulHardlink = true
 			continue
 		}
-		p.log.Log().Msgf("created hardlink: source(%s), target(%s)", match.clientEpPath, match.announcedEpPath)
+
+		linkType, err := tx.Link(match.ClientEpPath, match.AnnouncedEpPath)
+		if err != nil {
+			p.log.Error().Err(err).Msgf("error creating link: %s", match.ClientEpPath)
+			if p.cfg.Config.TransactionalLinking {
+				return p.rollbackLinks(tx, err)
+			}
+			linkErr = err
+			continue
+		}
+		p.log.Log().Msgf("created %s: source(%s), target(%s)", linkType, match.ClientEpPath, match.AnnouncedEpPath)
+		p.history.AddLink(match.ClientEpPath, match.AnnouncedEpPath, linkType)
 		successfulHardlink = true
 	}
 
 	if !successfulHardlink {
+		if linkErr != nil {
+			return domain.StatusFailedHardlink, errors.Wrap(linkErr, domain.StatusFailedHardlink.String())
+		}
 		return domain.StatusFailedHardlink, domain.StatusFailedHardlink.Error()
 	}
 
+	if p.req.DryRun {
+		return domain.StatusDryRunHardlink, nil
+	}
+
 	return domain.StatusSuccessfulHardlink, nil
 }
 
+// healthy reports whether the torrent can be used as a match, i.e. it's complete if required and not in one of the
+// excluded states.
+func (p *processor) healthy(t clients.Torrent) bool {
+	if p.cfg.Config.TorrentHealth.RequireComplete && t.Progress < 1 {
+		return false
+	}
+
+	return !slices.Contains(p.cfg.Config.TorrentHealth.ExcludedStates, string(t.State))
+}
+
+// episodeFiles returns the videos of a client torrent that are candidates for the pack, together with the episodes
+// they contain. If the torrent contains a single video, e.g. an episode with a sample, it's the episode of the
+// torrent. Otherwise every video is its own candidate and the episodes are taken from the file names, so e.g. every
+// episode of a mini pack like Show.S01E01-E03 counts. If none of the names are episodes, e.g. an episode with extras
+// that are named oddly, the largest video is the episode of the torrent. Samples and other excluded files are skipped.
+func (p *processor) episodeFiles(pack release.Pack, clientEntry entry, files []clients.File, exclusions *torrents.Exclusions, numbers *utils.EpisodeNumbers) []episodeFile {
+	videos := make([]clients.File, 0, len(files))
+	for _, f := range files {
+		if !utils.HasExtension(f.Path, p.videoExtensions()) || f.Size == 0 {
+			continue
+		}
+
+		if exclusions.Excluded(f.Path, f.Size) {
+			p.log.Debug().Msgf("skipping excluded file: %s", f.Path)
+			continue
+		}
+
+		// extras folders only contain specials, so they're skipped unless specials are matched
+		if torrents.IsExtra(f.Path) && !p.matchSpecials() && !pack.HasSeason(0) {
+			p.log.Debug().Msgf("skipping file in extras folder: %s", f.Path)
+			continue
+		}
+
+		videos = append(videos, f)
+	}
+
+	if len(videos) == 1 {
+		return []episodeFile{{f: videos[0], r: clientEntry.r}}
+	}
+
+	epFiles := make([]episodeFile, 0, len(videos))
+	parsed := false
+	for _, f := range videos {
+		fileRls := release.ParsePack(filepath.Base(f.Path)).Resolve(numbers, clientEntry.r.Series)
+		parsed = parsed || len(fileRls.Episodes) > 0
+		if len(fileRls.Episodes) == 0 || !p.includes(pack, fileRls) {
+			p.log.Debug().Msgf("skipping file that isn't an episode of the pack: %s", f.Path)
+			continue
+		}
+
+		// the same episode can be in a torrent more than once, e.g. in different formats, the largest one is used
+		idx := slices.IndexFunc(epFiles, func(e episodeFile) bool {
+			return e.r.Series == fileRls.Series && slices.Equal(e.r.Episodes, fileRls.Episodes)
+		})
+		if idx != -1 {
+			if f.Size > epFiles[idx].f.Size {
+				epFiles[idx] = episodeFile{f: f, r: fileRls}
+			}
+			continue
+		}
+
+		epFiles = append(epFiles, episodeFile{f: f, r: fileRls})
+	}
+
+	if !parsed && len(videos) > 0 {
+		largest := slices.MaxFunc(videos, func(a, b clients.File) int {
+			return cmp.Compare(a.Size, b.Size)
+		})
+		p.log.Debug().Msgf("no file is named like an episode, using the largest one: %s", largest.Path)
+
+		return []episodeFile{{f: largest, r: clientEntry.r}}
+	}
+
+	return epFiles
+}
+
+// videoExtensions returns the configured video extensions, or the default ones if none are configured.
+func (p *processor) videoExtensions() []string {
+	if len(p.cfg.Config.VideoExtensions) == 0 {
+		return domain.VideoExtensions
+	}
+
+	return p.cfg.Config.VideoExtensions
+}
+
+// exclusions returns the configured exclusion rules. Invalid patterns are rejected when the config is loaded, so
+// if compiling them fails anyway, only the patterns are skipped.
+func (p *processor) exclusions() *torrents.Exclusions {
+	exclusions, err := torrents.NewExclusions(p.cfg.Config.Exclusions)
+	if err != nil {
+		p.log.Error().Err(err).Msg("error compiling exclusions, skipping patterns")
+		exclusions, _ = torrents.NewExclusions(domain.Exclusions{
+			Samples: p.cfg.Config.Exclusions.Samples,
+			MinSize: p.cfg.Config.Exclusions.MinSize,
+		})
+	}
+
+	return exclusions
+}
+
+// isSidecar reports whether the file is a sidecar, i.e. it has one of the configured sidecar extensions and
+// isn't a video.
+func (p *processor) isSidecar(path string) bool {
+	return utils.HasExtension(path, p.cfg.Config.SidecarExtensions) && !utils.HasExtension(path, p.videoExtensions())
+}
+
+// includes reports whether the client release is a candidate for the pack. Specials are only candidates if
+// the specials policy allows matching them, or the pack explicitly contains season 0.
+func (p *processor) includes(pack release.Pack, clientRls release.Pack) bool {
+	if clientRls.Special() && !pack.HasSeason(0) {
+		return p.matchSpecials()
+	}
+
+	return pack.Includes(clientRls)
+}
+
+// matchSpecials reports whether the specials policy allows matching specials.
+func (p *processor) matchSpecials() bool {
+	return p.cfg.Config.Specials == domain.SpecialsMatch || p.cfg.Config.Specials == domain.SpecialsCount
+}
+
+// countSpecials reports whether matched specials count towards the found episodes and the smart mode threshold.
+func (p *processor) countSpecials(pack release.Pack) bool {
+	return pack.HasSeason(0) || p.cfg.Config.Specials == domain.SpecialsCount
+}
+
+// getEpisodeNumbers returns the alternative episode numberings of the show if any of the given releases needs
+// them, i.e. episodes of daily shows and, if anime mode is enabled, episodes with absolute numbers. Errors are
+// only logged, releases without a season just won't match.
+func (p *processor) getEpisodeNumbers(title string, releases []release.Pack) *utils.EpisodeNumbers {
+	needed := false
+	for _, r := range releases {
+		if r.NeedsResolve() && (!r.AirDate.IsZero() || p.cfg.Config.AnimeMode) {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	numbers, err := utils.GetEpisodeNumbers(title)
+	if err != nil {
+		p.log.Error().Err(err).Msgf("error getting episode numbers: %s", title)
+		return nil
+	}
+
+	if !p.cfg.Config.AnimeMode {
+		numbers.Absolute = nil
+	}
+
+	return numbers
+}
+
+// checkSmartModeThreshold checks every season of the pack on its own, so a multi season pack is only
+// grabbed if enough episodes of each of its seasons are in the client.
+func (p *processor) checkSmartModeThreshold(pack release.Pack, epsPerSeason map[int]map[int]struct{}) (domain.StatusCode, error) {
+	totalEpsPerSeason, err := utils.GetEpisodesPerSeasons(pack.Title)
+	if err != nil {
+		return domain.StatusEpisodeCountError, errors.Wrap(err, domain.StatusEpisodeCountError.String())
+	}
+
+	return p.checkThreshold(pack, epsPerSeason, totalEpsPerSeason)
+}
+
+// checkThreshold checks the found episodes of every season of the pack against its total episodes. Specials are
+// checked like any other season if they count towards the threshold, whatever seasons the pack contains.
+func (p *processor) checkThreshold(pack release.Pack, epsPerSeason map[int]map[int]struct{}, totalEpsPerSeason map[int]int) (domain.StatusCode, error) {
+	seasons := slices.Clone(pack.Seasons)
+	if pack.Complete {
+		seasons = make([]int, 0, len(totalEpsPerSeason))
+		for season := range totalEpsPerSeason {
+			if pack.HasSeason(season) {
+				seasons = append(seasons, season)
+			}
+		}
+	}
+
+	countSpecials := p.countSpecials(pack)
+	// specials are only checked if their number is known or some of them were found
+	if countSpecials && !slices.Contains(seasons, 0) && (totalEpsPerSeason[0] > 0 || len(epsPerSeason[0]) > 0) {
+		seasons = append(seasons, 0)
+	}
+	slices.Sort(seasons)
+
+	p.history.TotalEpisodes = 0
+
+	for _, season := range seasons {
+		totalEps := totalEpsPerSeason[season]
+		if season == 0 && countSpecials {
+			// tvmaze doesn't know about every special, the found ones exist nonetheless
+			totalEps = max(totalEps, len(epsPerSeason[0]))
+		}
+		if totalEps == 0 {
+			return domain.StatusEpisodeCountError, errors.Wrap(fmt.Errorf("failed to find episodes in season %d of %q",
+				season, pack.Title), domain.StatusEpisodeCountError.String())
+		}
+		p.history.TotalEpisodes += totalEps
+
+		foundEps := len(epsPerSeason[season])
+		percentEps := release.PercentOfTotalEpisodes(totalEps, foundEps)
+
+		if percentEps < p.cfg.Config.SmartModeThreshold {
+			return domain.StatusBelowThreshold, errors.Wrap(fmt.Errorf("found %d/%d (%.2f%%) episodes of season %d in client",
+				foundEps, totalEps, percentEps*100, season), domain.StatusBelowThreshold.String())
+		}
+	}
+
+	return domain.StatusSuccessfulMatch, nil
+}
+
+// packRejection is the reason a client episode didn't match a file of the pack.
+type packRejection struct {
+	packFile string
+	info     domain.CompareInfo
+}
+
+// rejectionRank returns how specific the reason of a rejected client episode and pack file pair is. The checks
+// run in this order, so a later reason means the files had more in common.
+func rejectionRank(code domain.StatusCode) int {
+	return slices.Index([]domain.StatusCode{
+		domain.StatusSizeMismatch,
+		domain.StatusSeasonMismatch,
+		domain.StatusEpisodeMismatch,
+		domain.StatusResolutionMismatch,
+		domain.StatusRlsGrpMismatch,
+	}, code)
+}
+
+// rollbackLinks removes all links and directories that were created for the request, so the torrent
+// client never starts a season pack in a partially linked folder.
+func (p *processor) rollbackLinks(tx *utils.LinkTransaction, linkErr error) (domain.StatusCode, error) {
+	p.log.Warn().Msgf("rolling back %d links after failed link", tx.Links())
+
+	if err := tx.Rollback(); err != nil {
+		p.log.Error().Err(err).Msg("error rolling back links")
+	}
+	p.history.Links = p.history.Links[:0]
+
+	return domain.StatusFailedHardlink, errors.Wrap(linkErr, "%s, rolled back all links", domain.StatusFailedHardlink.String())
+}
+
 func (p *processor) ParseTorrentHandler(c *gin.Context) {
 	p.log.Info().Msg("starting to parse season pack torrent")
 
+	p.history.Action = domain.HistoryActionParse
+
 	if err := json.NewDecoder(c.Request.Body).Decode(&p.req); err != nil {
 		p.log.Error().Err(err).Msgf("%s", domain.StatusDecodingError)
-		c.AbortWithStatusJSON(domain.StatusDecodingError.Code(), gin.H{
-			"statusCode": domain.StatusDecodingError.Code(),
-			"error":      err.Error(),
-		})
+		p.storeHistory(c.Request.Context(), domain.StatusDecodingError, err)
+		c.AbortWithStatusJSON(domain.StatusDecodingError.Code(), p.history)
+		return
+	}
+
+	if dryRun, _ := strconv.ParseBool(c.Query("dryRun")); dryRun {
+		p.req.DryRun = true
+	}
+
+	statusCode, err := p.parseTorrent(c.Request.Context())
+	p.storeHistory(c.Request.Context(), statusCode, err)
+
+	if p.req.DryRun {
+		p.log.Info().Msg("finished dry run, no hardlinks were created")
+		c.JSON(statusCode.Code(), p.history)
 		return
 	}
 
-	statusCode, err := p.parseTorrent()
 	if err != nil {
 		go func() {
 			if sendErr := p.noti.Send(statusCode, domain.NotificationPayload{
@@ -382,10 +924,7 @@ func (p *processor) ParseTorrentHandler(c *gin.Context) {
 		}()
 
 		p.log.Error().Err(err).Msg("error parsing torrent")
-		c.AbortWithStatusJSON(statusCode.Code(), gin.H{
-			"statusCode": statusCode.Code(),
-			"error":      err.Error(),
-		})
+		c.AbortWithStatusJSON(statusCode.Code(), p.history)
 		return
 	}
 
@@ -400,10 +939,10 @@ func (p *processor) ParseTorrentHandler(c *gin.Context) {
 	}()
 
 	p.log.Info().Msg("successfully parsed torrent and hardlinked episodes")
-	c.String(statusCode.Code(), statusCode.String())
+	c.JSON(statusCode.Code(), p.history)
 }
 
-func (p *processor) parseTorrent() (domain.StatusCode, error) {
+func (p *processor) parseTorrent(ctx context.Context) (domain.StatusCode, error) {
 	clientName := p.getClientName()
 
 	p.log.UpdateContext(func(c zerolog.Context) zerolog.Context {
@@ -436,57 +975,163 @@ func (p *processor) parseTorrent() (domain.StatusCode, error) {
 	parsedPackName := torrentInfo.BestName()
 	p.log.Debug().Msgf("parsed season pack name: %s", parsedPackName)
 
-	torrentEps, err := torrents.GetEpisodesFromTorrentInfo(torrentInfo)
+	torrentEps, err := torrents.GetEpisodesFromTorrentInfo(torrentInfo, p.videoExtensions())
 	if err != nil {
 		return domain.StatusGetEpisodesError, errors.Wrap(err, domain.StatusGetEpisodesError.String())
 	}
+	exclusions := p.exclusions()
+	torrentEps = slices.DeleteFunc(torrentEps, func(torrentEp torrents.Episode) bool {
+		if exclusions.Excluded(torrentEp.Path, torrentEp.Size) {
+			p.log.Debug().Msgf("skipping excluded file: %s", torrentEp.Path)
+			return true
+		}
+		return false
+	})
+	if len(torrentEps) == 0 {
+		return domain.StatusGetEpisodesError, errors.Wrap(errors.New("all video files are excluded"), domain.StatusGetEpisodesError.String())
+	}
+
+	// extras folders only contain specials, if any, so they're skipped if specials are ignored
+	if !p.matchSpecials() && !release.ParsePack(p.req.Name).HasSeason(0) {
+		torrentEps = slices.DeleteFunc(torrentEps, func(torrentEp torrents.Episode) bool {
+			if torrents.IsExtra(torrentEp.Path) {
+				p.log.Debug().Msgf("skipping file in extras folder: %s", torrentEp.Path)
+				return true
+			}
+			return false
+		})
+	}
+
 	for _, torrentEp := range torrentEps {
 		p.log.Debug().Msgf("found episode in pack: name(%s), size(%d)", torrentEp.Path, torrentEp.Size)
 	}
 
-	matches, ok := matchesMap.Load(p.req.Name)
-	if !ok {
+	pending, ok := p.findMatches(p.req.Name)
+	if !ok || pending.expired(p.cfg.Config.MatchTTL, time.Now()) {
 		return domain.StatusNoMatches, domain.StatusNoMatches.Error()
 	}
+	matches := pending.matches
+
+	sidecars := torrents.GetFilesFromTorrentInfo(torrentInfo, p.cfg.Config.SidecarExtensions)
+	sidecarMatches := make([]domain.Match, 0)
+	matches = slices.DeleteFunc(slices.Clone(matches), func(match domain.Match) bool {
+		if p.isSidecar(match.ClientEpPath) {
+			sidecarMatches = append(sidecarMatches, match)
+			return true
+		}
+		return false
+	})
+
+	episodeRls := make([]release.Pack, 0, len(matches)+len(torrentEps))
+	for _, match := range matches {
+		episodeRls = append(episodeRls, release.ParsePack(filepath.Base(match.ClientEpPath)))
+	}
+	for _, torrentEp := range torrentEps {
+		episodeRls = append(episodeRls, release.ParsePack(filepath.Base(torrentEp.Path)))
+	}
+	numbers := p.getEpisodeNumbers(release.ParsePack(p.req.Name).Title, episodeRls)
 
 	successfulEpMatch := false
 	successfulHardlink := false
+	var linkErr error
 
 	var matchedEpPath string
 	var compareInfo domain.CompareInfo
 	var targetEpPath string
 
 	targetPackDir := filepath.Join(clientCfg.PreImportPath, parsedPackName)
+	tx := utils.NewLinkTransaction(linkTypes(clientCfg))
 
 	for _, match := range matches {
+		// only the most specific reason is recorded for client episodes that don't match any file in the pack
+		var rejection *packRejection
+
 		for _, torrentEp := range torrentEps {
 			// reset targetEpPath for each checked torrentEp
 			targetEpPath = ""
 
-			matchedEpPath, compareInfo = release.MatchEpToSeasonPackEp(match.clientEpPath, match.clientEpSize,
-				torrentEp.Path, torrentEp.Size)
+			matchedEpPath, compareInfo = release.MatchEpToSeasonPackEp(match.ClientEpPath, match.ClientEpSize,
+				torrentEp.Path, torrentEp.Size, numbers)
 			if len(matchedEpPath) == 0 {
 				p.log.Debug().Msgf("%s: client(%s => %v), torrent(%s => %v)", compareInfo.StatusCode,
-					filepath.Base(match.clientEpPath), compareInfo.RejectValueA, torrentEp.Path, compareInfo.RejectValueB)
+					filepath.Base(match.ClientEpPath), compareInfo.RejectValueA, torrentEp.Path, compareInfo.RejectValueB)
+				if rejection == nil || rejectionRank(rejection.info.StatusCode) <= rejectionRank(compareInfo.StatusCode) {
+					rejection = &packRejection{packFile: torrentEp.Path, info: compareInfo}
+				}
 				continue
 			}
 			targetEpPath = filepath.Join(targetPackDir, matchedEpPath)
 			successfulEpMatch = true
+			clientEpRls := release.ParsePack(filepath.Base(match.ClientEpPath)).Resolve(numbers, 0)
+			p.history.AddMatch(domain.HistoryMatch{
+				Season:   clientEpRls.Series,
+				Episode:  clientEpRls.Episode,
+				Episodes: multiEpisodes(clientEpRls),
+				Path:     match.ClientEpPath,
+				Size:     match.ClientEpSize,
+			})
 
-			if err = utils.CreateHardlink(match.clientEpPath, targetEpPath); err != nil {
-				p.log.Error().Err(err).Msgf("error creating hardlink: %s", match.clientEpPath)
+			if p.req.DryRun {
+				p.history.AddLink(match.ClientEpPath, targetEpPath, linkTypes(clientCfg)[0])
+				successfulHardlink = true
+				break
+			}
+
+			linkType, err := tx.Link(match.ClientEpPath, targetEpPath)
+			if err != nil {
+				p.log.Error().Err(err).Msgf("error creating link: %s", match.ClientEpPath)
+				if p.cfg.Config.TransactionalLinking {
+					return p.rollbackLinks(tx, err)
+				}
+				linkErr = err
 				continue
 			}
-			p.log.Log().Msgf("created hardlink: source(%s), target(%s)", match.clientEpPath, targetEpPath)
+			p.log.Log().Msgf("created %s: source(%s), target(%s)", linkType, match.ClientEpPath, targetEpPath)
+			p.history.AddLink(match.ClientEpPath, targetEpPath, linkType)
 			successfulHardlink = true
 
 			break
 		}
 		if len(matchedEpPath) == 0 {
+			if rejection != nil {
+				p.history.AddRejection(match.ClientEpPath, rejection.packFile, rejection.info)
+			}
 			p.log.Error().Msgf("error matching episode to file in pack, skipping hardlink: %s",
-				filepath.Base(match.clientEpPath))
+				filepath.Base(match.ClientEpPath))
+			continue
+		}
+	}
+
+	// sidecars are only linked along with the episodes, if the pack contains a file with the same name and size
+	for _, match := range sidecarMatches {
+		if !successfulEpMatch {
+			break
+		}
+
+		idx := slices.IndexFunc(sidecars, func(sidecar torrents.Episode) bool {
+			return filepath.Base(sidecar.Path) == filepath.Base(match.ClientEpPath) && sidecar.Size == match.ClientEpSize
+		})
+		if idx == -1 {
+			p.log.Debug().Msgf("sidecar not found in pack, skipping: %s", filepath.Base(match.ClientEpPath))
+			continue
+		}
+		targetSidecarPath := filepath.Join(targetPackDir, sidecars[idx].Path)
+
+		if p.req.DryRun {
+			p.history.AddLink(match.ClientEpPath, targetSidecarPath, linkTypes(clientCfg)[0])
 			continue
 		}
+
+		linkType, err := tx.Link(match.ClientEpPath, targetSidecarPath)
+		if err != nil {
+			p.log.Error().Err(err).Msgf("error creating link: %s", match.ClientEpPath)
+			if p.cfg.Config.TransactionalLinking {
+				return p.rollbackLinks(tx, err)
+			}
+			continue
+		}
+		p.log.Log().Msgf("created %s: source(%s), target(%s)", linkType, match.ClientEpPath, targetSidecarPath)
+		p.history.AddLink(match.ClientEpPath, targetSidecarPath, linkType)
 	}
 
 	if !successfulEpMatch {
@@ -494,8 +1139,18 @@ func (p *processor) parseTorrent() (domain.StatusCode, error) {
 	}
 
 	if !successfulHardlink {
+		if linkErr != nil {
+			return domain.StatusFailedHardlink, errors.Wrap(linkErr, domain.StatusFailedHardlink.String())
+		}
 		return domain.StatusFailedHardlink, domain.StatusFailedHardlink.Error()
 	}
 
+	// the matches have been used, so they don't need to be kept any longer
+	p.deleteMatches(ctx, p.req.Name)
+
+	if p.req.DryRun {
+		return domain.StatusDryRunHardlink, nil
+	}
+
 	return domain.StatusSuccessfulHardlink, nil
 }
@@ -0,0 +1,916 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/clients"
+	"github.com/nuxencs/seasonpackarr/internal/config"
+	"github.com/nuxencs/seasonpackarr/internal/database"
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/logger"
+	"github.com/nuxencs/seasonpackarr/internal/release"
+	"github.com/nuxencs/seasonpackarr/internal/torrents"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient serves the given torrents and their files.
+type fakeClient struct {
+	torrents []clients.Torrent
+	files    map[string][]clients.File
+}
+
+func (c *fakeClient) Type() string                  { return "fake" }
+func (c *fakeClient) Login(_ context.Context) error { return nil }
+func (c *fakeClient) GetTorrents(_ context.Context) ([]clients.Torrent, error) {
+	return c.torrents, nil
+}
+func (c *fakeClient) GetFiles(_ context.Context, hash string) ([]clients.File, error) {
+	return c.files[hash], nil
+}
+func (c *fakeClient) AddTorrent(_ context.Context, _ []byte, _ clients.AddTorrentOptions) error {
+	return nil
+}
+
+// newTestProcessor returns a processor using a filesystem client, which serves numEpisodes
+// episodes of the given season pack from a temporary library directory.
+func newTestProcessor(t *testing.T, clientName string, packName string, numEpisodes int) (*processor, *domain.Client) {
+	t.Helper()
+
+	libraryDir := t.TempDir()
+	for i := 1; i <= numEpisodes; i++ {
+		epName := fmt.Sprintf("Series.Title.S01E%02d.1080p.WEB-DL.H.264-RlsGrp.mkv", i)
+		// the episodes created by torrents.TorrentFromRls contain "0", so the sizes match
+		require.NoError(t, os.WriteFile(filepath.Join(libraryDir, epName), []byte("0"), 0644))
+	}
+
+	client := &domain.Client{
+		Type:          domain.ClientTypeFilesystem,
+		Directories:   []string{libraryDir},
+		PreImportPath: t.TempDir(),
+	}
+
+	cfg := &config.AppConfig{Config: &domain.Config{
+		Version:  "dev",
+		LogLevel: "ERROR",
+		Clients:  map[string]*domain.Client{clientName: client},
+	}}
+
+	log := logger.New(cfg.Config)
+
+	db, err := database.NewDB(&domain.Config{ConfigPath: t.TempDir()}, log)
+	require.NoError(t, err)
+	require.NoError(t, db.Open())
+	t.Cleanup(func() { db.Close() })
+
+	p := newProcessor(log, cfg, nil, database.NewMatchRepo(log, db), database.NewHistoryRepo(log, db))
+	p.req = &request{Name: packName, ClientName: clientName}
+
+	return p, client
+}
+
+func Test_ProcessSeasonPack(t *testing.T) {
+	tests := []struct {
+		name             string
+		packName         string
+		numEpisodes      int
+		parseTorrentFile bool
+		want             domain.StatusCode
+		wantLinks        int
+		wantRejections   int
+	}{
+		{
+			name:        "hardlink_episodes",
+			packName:    "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			numEpisodes: 3,
+			want:        domain.StatusSuccessfulHardlink,
+			wantLinks:   3,
+		},
+		{
+			name:             "parse_torrent_file",
+			packName:         "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			numEpisodes:      3,
+			parseTorrentFile: true,
+			want:             domain.StatusSuccessfulMatch,
+			wantLinks:        0,
+		},
+		{
+			name:           "different_group",
+			packName:       "Series.Title.S01.1080p.WEB-DL.H.264-OtherGrp",
+			numEpisodes:    3,
+			want:           domain.StatusNoMatches,
+			wantLinks:      0,
+			wantRejections: 3,
+		},
+		{
+			name:        "no_episodes",
+			packName:    "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			numEpisodes: 0,
+			want:        domain.StatusNoMatches,
+			wantLinks:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, client := newTestProcessor(t, tt.name, tt.packName, tt.numEpisodes)
+			p.cfg.Config.ParseTorrentFile = tt.parseTorrentFile
+
+			got, _ := p.processSeasonPack(context.Background())
+			assert.Equal(t, tt.want, got)
+
+			links, _ := filepath.Glob(filepath.Join(client.PreImportPath, "*", "*.mkv"))
+			assert.Len(t, links, tt.wantLinks)
+
+			assert.Len(t, p.history.Links, tt.wantLinks)
+			assert.Len(t, p.history.Rejections, tt.wantRejections)
+		})
+	}
+}
+
+func Test_ParseTorrent(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	p, client := newTestProcessor(t, "parse_torrent", packName, 3)
+	p.cfg.Config.ParseTorrentFile = true
+
+	got, err := p.processSeasonPack(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusSuccessfulMatch, got)
+
+	// simulate a restart between the pack and the parse request
+	matchesMap.Clear()
+	n, err := loadMatches(context.Background(), p.matchRepo)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	torrentBytes, err := torrents.TorrentFromRls(packName, 5)
+	require.NoError(t, err)
+	p.req.Torrent = json.RawMessage(strconv.Quote(base64.StdEncoding.EncodeToString(torrentBytes)))
+
+	got, err = p.parseTorrent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSuccessfulHardlink, got)
+
+	links, _ := filepath.Glob(filepath.Join(client.PreImportPath, packName, "*.mkv"))
+	assert.Len(t, links, 3)
+
+	// the used matches are evicted
+	_, ok := matchesMap.Load(packName)
+	assert.False(t, ok)
+
+	pending, err := p.matchRepo.FindAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func Test_ParseTorrentRejections(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	p, _ := newTestProcessor(t, "parse_torrent_rejections", packName, 3)
+	p.cfg.Config.ParseTorrentFile = true
+
+	got, err := p.processSeasonPack(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusSuccessfulMatch, got)
+
+	// the pack lacks the third episode of the client
+	torrentBytes, err := torrents.TorrentFromRls(packName, 2)
+	require.NoError(t, err)
+	p.req.Torrent = json.RawMessage(strconv.Quote(base64.StdEncoding.EncodeToString(torrentBytes)))
+	p.history = domain.NewHistory(domain.HistoryActionParse)
+
+	got, err = p.parseTorrent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSuccessfulHardlink, got)
+
+	// only the unmatched client episode is rejected, once
+	require.Len(t, p.history.Rejections, 1)
+	assert.Equal(t, domain.StatusEpisodeMismatch, p.history.Rejections[0].StatusCode)
+	assert.Contains(t, p.history.Rejections[0].Candidate, "S01E03")
+}
+
+func Test_MultiSeasonPack(t *testing.T) {
+	packName := "Series.Title.S01-S02.1080p.WEB-DL.H.264-RlsGrp"
+
+	p, client := newTestProcessor(t, "multi_season", packName, 3)
+	p.cfg.Config.ParseTorrentFile = true
+
+	// episodes of the second season are matched as well, the ones of the third season aren't
+	for _, epName := range []string{
+		"Series.Title.S02E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+		"Series.Title.S02E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+		"Series.Title.S03E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(client.Directories[0], epName), []byte("0"), 0644))
+	}
+
+	got, err := p.processSeasonPack(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusSuccessfulMatch, got)
+	assert.Equal(t, 5, p.history.FoundEpisodes)
+
+	torrentBytes, err := torrents.TorrentFromRls(packName, 3)
+	require.NoError(t, err)
+	p.req.Torrent = json.RawMessage(strconv.Quote(base64.StdEncoding.EncodeToString(torrentBytes)))
+
+	got, err = p.parseTorrent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSuccessfulHardlink, got)
+
+	// the episodes are linked into the season folders of the pack
+	for season, wantLinks := range map[string]int{"S01": 3, "S02": 2} {
+		seasonDir := strings.Replace(packName, "S01-S02", season, 1)
+		links, _ := filepath.Glob(filepath.Join(client.PreImportPath, packName, seasonDir, "*.mkv"))
+		assert.Len(t, links, wantLinks, season)
+	}
+}
+
+func Test_MultiEpisodeFiles(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	p, client := newTestProcessor(t, "multi_episode", packName, 1)
+	p.cfg.Config.ParseTorrentFile = true
+
+	epName := "Series.Title.S01E02E03.1080p.WEB-DL.H.264-RlsGrp.mkv"
+	require.NoError(t, os.WriteFile(filepath.Join(client.Directories[0], epName), []byte("0"), 0644))
+
+	got, err := p.processSeasonPack(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusSuccessfulMatch, got)
+
+	// the double episode counts as two episodes
+	assert.Equal(t, 3, p.history.FoundEpisodes)
+	require.Len(t, p.history.Matches, 2)
+	for _, m := range p.history.Matches {
+		if m.Episode == 2 {
+			assert.Equal(t, []int{2, 3}, m.Episodes)
+		}
+	}
+}
+
+func Test_Specials(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	tests := []struct {
+		name             string
+		specials         string
+		parseTorrentFile bool
+		want             domain.StatusCode
+		wantMatches      int
+		wantFound        int
+		wantLinks        int
+	}{
+		{
+			name:             "ignore",
+			specials:         domain.SpecialsIgnore,
+			parseTorrentFile: true,
+			want:             domain.StatusSuccessfulMatch,
+			wantMatches:      3,
+			wantFound:        3,
+		},
+		{
+			name:             "match",
+			specials:         domain.SpecialsMatch,
+			parseTorrentFile: true,
+			want:             domain.StatusSuccessfulMatch,
+			wantMatches:      4,
+			wantFound:        3,
+		},
+		{
+			name:             "count",
+			specials:         domain.SpecialsCount,
+			parseTorrentFile: true,
+			want:             domain.StatusSuccessfulMatch,
+			wantMatches:      4,
+			wantFound:        4,
+		},
+		{
+			name:        "match_without_torrent",
+			specials:    domain.SpecialsMatch,
+			want:        domain.StatusSuccessfulHardlink,
+			wantMatches: 4,
+			wantFound:   3,
+			wantLinks:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, client := newTestProcessor(t, "specials_"+tt.name, packName, 3)
+			p.cfg.Config.Specials = tt.specials
+			p.cfg.Config.ParseTorrentFile = tt.parseTorrentFile
+
+			epName := "Series.Title.S00E01.1080p.WEB-DL.H.264-RlsGrp.mkv"
+			require.NoError(t, os.WriteFile(filepath.Join(client.Directories[0], epName), []byte("0"), 0644))
+
+			got, err := p.processSeasonPack(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			assert.Len(t, p.history.Matches, tt.wantMatches)
+			assert.Equal(t, tt.wantFound, p.history.FoundEpisodes)
+			assert.Len(t, p.history.Links, tt.wantLinks)
+		})
+	}
+}
+
+func Test_CheckThreshold(t *testing.T) {
+	episodes := func(numbers ...int) map[int]struct{} {
+		eps := make(map[int]struct{})
+		for _, n := range numbers {
+			eps[n] = struct{}{}
+		}
+		return eps
+	}
+
+	tests := []struct {
+		name         string
+		packName     string
+		specials     string
+		epsPerSeason map[int]map[int]struct{}
+		totalEps     map[int]int
+		want         domain.StatusCode
+		wantTotal    int
+	}{
+		{
+			name:         "ignore",
+			packName:     "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			specials:     domain.SpecialsIgnore,
+			epsPerSeason: map[int]map[int]struct{}{1: episodes(1, 2, 3)},
+			totalEps:     map[int]int{0: 2, 1: 3},
+			want:         domain.StatusSuccessfulMatch,
+			wantTotal:    3,
+		},
+		{
+			name:         "count",
+			packName:     "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			specials:     domain.SpecialsCount,
+			epsPerSeason: map[int]map[int]struct{}{0: episodes(1, 2), 1: episodes(1, 2, 3)},
+			totalEps:     map[int]int{0: 2, 1: 3},
+			want:         domain.StatusSuccessfulMatch,
+			wantTotal:    5,
+		},
+		{
+			name:         "count_missing_specials",
+			packName:     "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp",
+			specials:     domain.SpecialsCount,
+			epsPerSeason: map[int]map[int]struct{}{1: episodes(1, 2, 3)},
+			totalEps:     map[int]int{0: 2, 1: 3},
+			want:         domain.StatusBelowThreshold,
+		},
+		{
+			name:         "count_unknown_specials",
+			packName:     "Series.Title.S01-S02.1080p.WEB-DL.H.264-RlsGrp",
+			specials:     domain.SpecialsCount,
+			epsPerSeason: map[int]map[int]struct{}{0: episodes(1), 1: episodes(1, 2, 3), 2: episodes(1, 2)},
+			totalEps:     map[int]int{1: 3, 2: 2},
+			want:         domain.StatusSuccessfulMatch,
+			wantTotal:    6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProcessor(t, "threshold_"+tt.name, tt.packName, 0)
+			p.cfg.Config.Specials = tt.specials
+			p.cfg.Config.SmartModeThreshold = 0.75
+
+			got, _ := p.checkThreshold(release.ParsePack(tt.packName), tt.epsPerSeason, tt.totalEps)
+			assert.Equal(t, tt.want, got)
+
+			if tt.want == domain.StatusSuccessfulMatch {
+				assert.Equal(t, tt.wantTotal, p.history.TotalEpisodes)
+			}
+		})
+	}
+}
+
+// torrentFromDir returns the encoded torrent of the given directory, as it's sent by autobrr.
+func torrentFromDir(t *testing.T, dir string) json.RawMessage {
+	t.Helper()
+
+	info := metainfo.Info{PieceLength: 256 * 1024}
+	require.NoError(t, info.BuildFromFilePath(dir))
+	infoBytes, err := bencode.Marshal(info)
+	require.NoError(t, err)
+
+	var torrentBytes bytes.Buffer
+	require.NoError(t, (&metainfo.MetaInfo{InfoBytes: infoBytes}).Write(&torrentBytes))
+
+	return json.RawMessage(strconv.Quote(base64.StdEncoding.EncodeToString(torrentBytes.Bytes())))
+}
+
+func Test_Sidecars(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	tests := []struct {
+		name             string
+		parseTorrentFile bool
+		want             domain.StatusCode
+		wantLinks        []string
+	}{
+		{
+			name:             "parse_torrent_file",
+			parseTorrentFile: true,
+			want:             domain.StatusSuccessfulHardlink,
+			wantLinks: []string{
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.en.srt",
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mp4",
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mp4",
+			},
+		},
+		{
+			name: "without_torrent",
+			want: domain.StatusSuccessfulHardlink,
+			wantLinks: []string{
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mp4",
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mp4",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, client := newTestProcessor(t, "sidecars_"+tt.name, packName, 0)
+			p.cfg.Config.ParseTorrentFile = tt.parseTorrentFile
+			p.cfg.Config.SidecarExtensions = []string{".srt"}
+
+			packDir := filepath.Join(t.TempDir(), packName)
+			require.NoError(t, os.Mkdir(packDir, 0755))
+
+			files := map[string][2]string{
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mp4":    {"0", "0"},
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.en.srt": {"subtitle", "subtitle"},
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mp4":    {"0", "0"},
+				// the sidecar in the pack differs from the one in the client, so it's not linked
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.en.srt": {"subtitle", "other subtitle"},
+			}
+			for name, content := range files {
+				require.NoError(t, os.WriteFile(filepath.Join(client.Directories[0], name), []byte(content[0]), 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(packDir, name), []byte(content[1]), 0644))
+			}
+
+			got, err := p.processSeasonPack(context.Background())
+			require.NoError(t, err)
+
+			if tt.parseTorrentFile {
+				require.Equal(t, domain.StatusSuccessfulMatch, got)
+
+				p.req.Torrent = torrentFromDir(t, packDir)
+
+				got, err = p.parseTorrent(context.Background())
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			// sidecars aren't episodes
+			for _, m := range p.history.Matches {
+				assert.Equal(t, ".mp4", filepath.Ext(m.Path))
+			}
+
+			links, _ := filepath.Glob(filepath.Join(client.PreImportPath, packName, "*"))
+			for i := range links {
+				links[i] = filepath.Base(links[i])
+			}
+			assert.ElementsMatch(t, tt.wantLinks, links)
+		})
+	}
+}
+
+func Test_Exclusions(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	tests := []struct {
+		name       string
+		exclusions domain.Exclusions
+		want       domain.StatusCode
+		wantLinks  []string
+	}{
+		{
+			name:       "samples",
+			exclusions: domain.Exclusions{Samples: true},
+			want:       domain.StatusSuccessfulHardlink,
+			wantLinks: []string{
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			},
+		},
+		{
+			// the sample is sorted first, so it's linked instead of the episode
+			name:       "samples_disabled",
+			exclusions: domain.Exclusions{},
+			want:       domain.StatusSuccessfulHardlink,
+			wantLinks: []string{
+				"Sample/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			},
+		},
+		{
+			name:       "min_size",
+			exclusions: domain.Exclusions{MinSize: 1},
+			want:       domain.StatusNoMatches,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, client := newTestProcessor(t, "exclusions_"+tt.name, packName, 2)
+			p.cfg.Config.ParseTorrentFile = true
+			p.cfg.Config.Exclusions = tt.exclusions
+
+			got, _ := p.processSeasonPack(context.Background())
+			if tt.want == domain.StatusNoMatches {
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			require.Equal(t, domain.StatusSuccessfulMatch, got)
+
+			packDir := filepath.Join(t.TempDir(), packName)
+			require.NoError(t, os.MkdirAll(filepath.Join(packDir, "Sample"), 0755))
+			for _, name := range []string{
+				"Sample/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+				"Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+				"Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			} {
+				require.NoError(t, os.WriteFile(filepath.Join(packDir, name), []byte("0"), 0644))
+			}
+			p.req.Torrent = torrentFromDir(t, packDir)
+
+			got, err := p.parseTorrent(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			links := make([]string, 0)
+			for _, l := range p.history.Links {
+				rel, err := filepath.Rel(filepath.Join(client.PreImportPath, packName), l.Target)
+				require.NoError(t, err)
+				links = append(links, filepath.ToSlash(rel))
+			}
+			assert.ElementsMatch(t, tt.wantLinks, links)
+		})
+	}
+}
+
+func Test_MultiFileTorrents(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	clientName := "multi_file"
+
+	p, client := newTestProcessor(t, clientName, packName, 0)
+	p.cfg.Config.Exclusions = domain.Exclusions{Samples: true}
+
+	miniPackName := "Series.Title.S01E01-E03.1080p.WEB-DL.H.264-RlsGrp"
+	fake := &fakeClient{
+		torrents: []clients.Torrent{
+			{Hash: "minipack", Name: miniPackName, SavePath: client.Directories[0]},
+			{Hash: "episode", Name: "Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp", SavePath: client.Directories[0]},
+			{Hash: "odd_names", Name: "Series.Title.S01E05.1080p.WEB-DL.H.264-RlsGrp", SavePath: client.Directories[0]},
+		},
+		files: map[string][]clients.File{
+			"minipack": {
+				{Path: miniPackName + "/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv", Size: 1},
+				{Path: miniPackName + "/Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv", Size: 1},
+				{Path: miniPackName + "/Series.Title.S01E03.1080p.WEB-DL.H.264-RlsGrp.mkv", Size: 1},
+				{Path: miniPackName + "/Sample/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv", Size: 1},
+			},
+			"episode": {
+				{Path: "Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp.mkv", Size: 1},
+				{Path: "Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp/Sample/sample.mkv", Size: 1},
+			},
+			// none of the videos are named like an episode, so the largest one is the episode
+			"odd_names": {
+				{Path: "Series.Title.S01E05.1080p.WEB-DL.H.264-RlsGrp/behind-the-scenes.mkv", Size: 1},
+				{Path: "Series.Title.S01E05.1080p.WEB-DL.H.264-RlsGrp/rlsgrp-st1e5-1080p.mkv", Size: 2},
+			},
+		},
+	}
+	for _, files := range fake.files {
+		for _, f := range files {
+			path := filepath.Join(client.Directories[0], f.Path)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte("0"), 0644))
+		}
+	}
+	clientMap.Store(clientName, fake)
+	t.Cleanup(func() { clientMap.Delete(clientName) })
+
+	got, err := p.processSeasonPack(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSuccessfulHardlink, got)
+
+	// every episode of the mini pack is its own match
+	assert.Equal(t, 5, p.history.FoundEpisodes)
+	require.Len(t, p.history.Matches, 5)
+	for i, m := range p.history.Matches {
+		assert.Equal(t, i+1, m.Episode)
+		assert.NotContains(t, m.Path, "Sample")
+	}
+	assert.Contains(t, p.history.Matches[4].Path, "rlsgrp-st1e5-1080p.mkv")
+
+	links, _ := filepath.Glob(filepath.Join(client.PreImportPath, packName, "*.mkv"))
+	assert.Len(t, links, 5)
+}
+
+func Test_TorrentHealth(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	health := domain.TorrentHealth{RequireComplete: true, ExcludedStates: []string{"error"}}
+
+	tests := []struct {
+		name           string
+		torrents       []clients.Torrent
+		health         domain.TorrentHealth
+		want           domain.StatusCode
+		wantMatches    int
+		wantRejections int
+	}{
+		{
+			name: "healthy",
+			torrents: []clients.Torrent{
+				{Name: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateSeeding, Progress: 1},
+				{Name: "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateDownloading, Progress: 0.03},
+				{Name: "Series.Title.S01E03.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateError, Progress: 1},
+			},
+			health:         health,
+			want:           domain.StatusSuccessfulMatch,
+			wantMatches:    1,
+			wantRejections: 2,
+		},
+		{
+			name: "all_incomplete",
+			torrents: []clients.Torrent{
+				{Name: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateDownloading, Progress: 0.5},
+				{Name: "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateError, Progress: 1},
+			},
+			health:         health,
+			want:           domain.StatusIncompleteTorrent,
+			wantRejections: 2,
+		},
+		{
+			name: "disabled",
+			torrents: []clients.Torrent{
+				{Name: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateDownloading, Progress: 0.5},
+				{Name: "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp", State: domain.TorrentStateError, Progress: 1},
+			},
+			want:        domain.StatusSuccessfulMatch,
+			wantMatches: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientName := "torrent_health_" + tt.name
+
+			p, client := newTestProcessor(t, clientName, packName, 0)
+			p.cfg.Config.ParseTorrentFile = true
+			p.cfg.Config.TorrentHealth = tt.health
+
+			fake := &fakeClient{files: make(map[string][]clients.File)}
+			for _, torrent := range tt.torrents {
+				torrent.Hash = torrent.Name
+				torrent.SavePath = client.Directories[0]
+				fake.torrents = append(fake.torrents, torrent)
+				fake.files[torrent.Hash] = []clients.File{{Path: torrent.Name + ".mkv", Size: 1}}
+			}
+			clientMap.Store(clientName, fake)
+			t.Cleanup(func() { clientMap.Delete(clientName) })
+
+			got, _ := p.processSeasonPack(context.Background())
+			assert.Equal(t, tt.want, got)
+
+			assert.Len(t, p.history.Matches, tt.wantMatches)
+			require.Len(t, p.history.Rejections, tt.wantRejections)
+			for _, r := range p.history.Rejections {
+				assert.Equal(t, domain.StatusIncompleteTorrent, r.StatusCode)
+			}
+		})
+	}
+}
+
+func Test_TorrentFilters(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	clientName := "torrent_filters"
+
+	p, client := newTestProcessor(t, clientName, packName, 0)
+	p.cfg.Config.ParseTorrentFile = true
+	client.Filters = domain.TorrentFilters{
+		IncludeCategories: []string{"tv-sonarr", "cross-seed"},
+		ExcludeTags:       []string{"cross-seed"},
+	}
+
+	fake := &fakeClient{files: make(map[string][]clients.File)}
+	for _, torrent := range []clients.Torrent{
+		{Name: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp", Category: "tv-sonarr"},
+		{Name: "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp", Category: "archive"},
+		{Name: "Series.Title.S01E03.1080p.WEB-DL.H.264-RlsGrp", Category: "cross-seed", Tags: []string{"cross-seed"}},
+		{Name: "Series.Title.S01E04.1080p.WEB-DL.H.264-RlsGrp", Category: "cross-seed"},
+	} {
+		torrent.Hash = torrent.Name
+		torrent.SavePath = client.Directories[0]
+		fake.torrents = append(fake.torrents, torrent)
+		fake.files[torrent.Hash] = []clients.File{{Path: torrent.Name + ".mkv", Size: 1}}
+	}
+	clientMap.Store(clientName, fake)
+	t.Cleanup(func() { clientMap.Delete(clientName) })
+
+	got, err := p.processSeasonPack(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSuccessfulMatch, got)
+
+	episodes := make([]int, 0)
+	for _, m := range p.history.Matches {
+		episodes = append(episodes, m.Episode)
+	}
+	assert.ElementsMatch(t, []int{1, 4}, episodes)
+}
+
+func Test_SweepMatches(t *testing.T) {
+	p, _ := newTestProcessor(t, "sweep_matches", "", 0)
+	ctx := context.Background()
+
+	expiredName := "Expired.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	freshName := "Fresh.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+	matches := []domain.Match{{ClientEpPath: "/data/ep.mkv", ClientEpSize: 1, AnnouncedEpPath: "/pre-import/ep.mkv"}}
+
+	p.storeMatches(ctx, expiredName, matches)
+	time.Sleep(200 * time.Millisecond)
+	p.storeMatches(ctx, freshName, matches)
+
+	// a ttl of 0 keeps everything
+	before := sweepMatches(ctx, p.log, p.matchRepo, 0)
+	_, ok := matchesMap.Load(expiredName)
+	assert.True(t, ok)
+
+	after := sweepMatches(ctx, p.log, p.matchRepo, 100*time.Millisecond)
+	assert.Less(t, after.pending, before.pending)
+	assert.Equal(t, matchesMap.Size(), after.pending)
+
+	_, ok = matchesMap.Load(expiredName)
+	assert.False(t, ok)
+	_, ok = matchesMap.Load(freshName)
+	assert.True(t, ok)
+
+	pending, err := p.matchRepo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, freshName, pending[0].ReleaseName)
+
+	p.deleteMatches(ctx, freshName)
+}
+
+func Test_DryRun(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	p, client := newTestProcessor(t, "dry_run", packName, 3)
+	handler := NewServer(logger.New(p.cfg.Config), p.cfg, nil, p.matchRepo, p.historyRepo).Handler()
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+	}{
+		{
+			name: "query_parameter",
+			url:  "/api/pack?dryRun=true",
+			body: fmt.Sprintf(`{"name":%q,"clientname":"dry_run"}`, packName),
+		},
+		{
+			name: "request_field",
+			url:  "/api/pack",
+			body: fmt.Sprintf(`{"name":%q,"clientname":"dry_run","dryRun":true}`, packName),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body)))
+			assert.Equal(t, domain.StatusDryRunHardlink.Code(), w.Code)
+
+			var report domain.History
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.True(t, report.DryRun)
+			require.Len(t, report.Matches, 3)
+			assert.Equal(t, 1, report.Matches[0].Season)
+			assert.NotEmpty(t, report.Matches[0].Hash)
+			assert.Equal(t, 3, report.FoundEpisodes)
+			require.Len(t, report.Links, 3)
+			assert.Equal(t, filepath.Join(client.PreImportPath, packName), filepath.Dir(report.Links[0].Target))
+
+			// nothing was linked or stored
+			links, _ := filepath.Glob(filepath.Join(client.PreImportPath, "*", "*.mkv"))
+			assert.Empty(t, links)
+
+			_, ok := matchesMap.Load(packName)
+			assert.False(t, ok)
+
+			h, err := p.historyRepo.FindByID(context.Background(), report.ID)
+			require.NoError(t, err)
+			assert.True(t, h.DryRun)
+		})
+	}
+}
+
+func Test_DryRunParse(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	p, client := newTestProcessor(t, "dry_run_parse", packName, 3)
+	p.cfg.Config.ParseTorrentFile = true
+	handler := NewServer(logger.New(p.cfg.Config), p.cfg, nil, p.matchRepo, p.historyRepo).Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/pack?dryRun=true",
+		strings.NewReader(fmt.Sprintf(`{"name":%q,"clientname":"dry_run_parse"}`, packName))))
+	require.Equal(t, domain.StatusSuccessfulMatch.Code(), w.Code)
+
+	// the matches are only kept for dry runs
+	_, ok := dryRunMatchesMap.Load(packName)
+	assert.True(t, ok)
+	_, ok = matchesMap.Load(packName)
+	assert.False(t, ok)
+
+	pending, err := p.matchRepo.FindAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	torrentBytes, err := torrents.TorrentFromRls(packName, 5)
+	require.NoError(t, err)
+	body := fmt.Sprintf(`{"name":%q,"clientname":"dry_run_parse","torrent":%q}`, packName,
+		base64.StdEncoding.EncodeToString(torrentBytes))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/parse?dryRun=true", strings.NewReader(body)))
+	assert.Equal(t, domain.StatusDryRunHardlink.Code(), w.Code)
+
+	var report domain.History
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Links, 3)
+
+	links, _ := filepath.Glob(filepath.Join(client.PreImportPath, "*", "*.mkv"))
+	assert.Empty(t, links)
+
+	_, ok = dryRunMatchesMap.Load(packName)
+	assert.False(t, ok)
+}
+
+func Test_TransactionalLinking(t *testing.T) {
+	packName := "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp"
+
+	tests := []struct {
+		name                 string
+		transactionalLinking bool
+		want                 domain.StatusCode
+		wantLinks            int
+	}{
+		{
+			name:                 "transactional_linking",
+			transactionalLinking: true,
+			want:                 domain.StatusFailedHardlink,
+			wantLinks:            0,
+		},
+		{
+			name:                 "partial_linking",
+			transactionalLinking: false,
+			want:                 domain.StatusSuccessfulHardlink,
+			wantLinks:            2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, client := newTestProcessor(t, tt.name, packName, 3)
+			p.cfg.Config.TransactionalLinking = tt.transactionalLinking
+
+			// an existing file in the pack folder makes linking the second episode fail
+			packDir := filepath.Join(client.PreImportPath, packName)
+			require.NoError(t, os.MkdirAll(packDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(packDir, "Series.Title.S01E02.1080p.WEB-DL.H.264-RlsGrp.mkv"), nil, 0644))
+
+			got, err := p.processSeasonPack(context.Background())
+			assert.Equal(t, tt.want, got)
+			if tt.transactionalLinking {
+				assert.ErrorContains(t, err, "rolled back")
+			}
+
+			links := 0
+			files, _ := filepath.Glob(filepath.Join(packDir, "*.mkv"))
+			for _, f := range files {
+				if info, _ := os.Stat(f); info.Size() > 0 {
+					links++
+				}
+			}
+			assert.Equal(t, tt.wantLinks, links)
+			assert.Len(t, p.history.Links, tt.wantLinks)
+		})
+	}
+}
@@ -23,23 +23,39 @@ import (
 var ErrServerClosed = http.ErrServerClosed
 
 type Server struct {
-	log  logger.Logger
-	cfg  *config.AppConfig
-	noti domain.Sender
+	log         logger.Logger
+	cfg         *config.AppConfig
+	noti        domain.Sender
+	matchRepo   domain.MatchRepo
+	historyRepo domain.HistoryRepo
 
 	httpServer http.Server
+	cancel     context.CancelFunc
 }
 
-func NewServer(log logger.Logger, config *config.AppConfig, notification domain.Sender) *Server {
+func NewServer(log logger.Logger, config *config.AppConfig, notification domain.Sender, matchRepo domain.MatchRepo,
+	historyRepo domain.HistoryRepo) *Server {
 	return &Server{
-		log:  log,
-		cfg:  config,
-		noti: notification,
+		log:         log,
+		cfg:         config,
+		noti:        notification,
+		matchRepo:   matchRepo,
+		historyRepo: historyRepo,
 	}
 }
 
 func (s *Server) Open() error {
-	var err error
+	n, err := loadMatches(context.Background(), s.matchRepo)
+	if err != nil {
+		return errors.Wrap(err, "could not load pending matches")
+	}
+	s.log.Info().Msgf("Loaded %d pending matches", n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go runMatchSweeper(ctx, s.log.With().Str("module", "sweeper").Logger(), s.cfg, s.matchRepo)
+
 	addr := fmt.Sprintf("%s:%d", s.cfg.Config.Host, s.cfg.Config.Port)
 
 	for _, proto := range []string{"tcp", "tcp4", "tcp6"} {
@@ -75,6 +91,10 @@ func (s *Server) tryToServe(addr, proto string) error {
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.log.Info().Msg("Shutting down the server gracefully...")
+	if s.cancel != nil {
+		s.cancel()
+	}
+
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
@@ -98,7 +118,8 @@ func (s *Server) Handler() http.Handler {
 
 		api.Use(s.AuthMiddleware())
 		{
-			newWebhookHandler(s.log, s.cfg, s.noti).Routes(api.Group("/"))
+			newWebhookHandler(s.log, s.cfg, s.noti, s.matchRepo, s.historyRepo).Routes(api.Group("/"))
+			newHistoryHandler(s.log, s.historyRepo).Routes(api.Group("/history"))
 		}
 	}
 
@@ -12,16 +12,21 @@ import (
 )
 
 type webhookHandler struct {
-	log  logger.Logger
-	cfg  *config.AppConfig
-	noti domain.Sender
+	log         logger.Logger
+	cfg         *config.AppConfig
+	noti        domain.Sender
+	matchRepo   domain.MatchRepo
+	historyRepo domain.HistoryRepo
 }
 
-func newWebhookHandler(log logger.Logger, cfg *config.AppConfig, notification domain.Sender) *webhookHandler {
+func newWebhookHandler(log logger.Logger, cfg *config.AppConfig, notification domain.Sender, matchRepo domain.MatchRepo,
+	historyRepo domain.HistoryRepo) *webhookHandler {
 	return &webhookHandler{
-		log:  log,
-		cfg:  cfg,
-		noti: notification,
+		log:         log,
+		cfg:         cfg,
+		noti:        notification,
+		matchRepo:   matchRepo,
+		historyRepo: historyRepo,
 	}
 }
 
@@ -31,9 +36,9 @@ func (h *webhookHandler) Routes(r *gin.RouterGroup) {
 }
 
 func (h *webhookHandler) pack(c *gin.Context) {
-	newProcessor(h.log, h.cfg, h.noti).ProcessSeasonPackHandler(c)
+	newProcessor(h.log, h.cfg, h.noti, h.matchRepo, h.historyRepo).ProcessSeasonPackHandler(c)
 }
 
 func (h *webhookHandler) parse(c *gin.Context) {
-	newProcessor(h.log, h.cfg, h.noti).ParseTorrentHandler(c)
+	newProcessor(h.log, h.cfg, h.noti, h.matchRepo, h.historyRepo).ParseTorrentHandler(c)
 }
@@ -5,11 +5,15 @@ package payload
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"text/template"
 	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
 )
 
 const payloadPack = `
@@ -94,8 +98,75 @@ func ExecRequest(url string, body io.Reader, apiToken string) error {
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("Completed the request with the following response: %d\n"+
-		"For more details take a look at the logs!", resp.StatusCode)
+	fmt.Printf("Completed the request with the following response: %d\n", resp.StatusCode)
+
+	var report domain.History
+	if err = json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		fmt.Println("For more details take a look at the logs!")
+		return nil
+	}
+
+	printReport(os.Stdout, &report)
 
 	return nil
 }
+
+// episodeTag returns the episode tag of a match, e.g. S01E01 or S01E01E02 for multi episode files.
+func episodeTag(m domain.HistoryMatch) string {
+	if len(m.Episodes) == 0 {
+		return fmt.Sprintf("S%02dE%02d", m.Season, m.Episode)
+	}
+
+	tag := fmt.Sprintf("S%02d", m.Season)
+	for _, ep := range m.Episodes {
+		tag += fmt.Sprintf("E%02d", ep)
+	}
+
+	return tag
+}
+
+// printReport writes a human-readable summary of the decision report returned by the api.
+func printReport(w io.Writer, report *domain.History) {
+	fmt.Fprintf(w, "Status: %s (%d)\n", report.Status, report.StatusCode)
+	if report.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", report.Error)
+	}
+	if report.DryRun {
+		fmt.Fprintln(w, "Dry run: no hardlinks were created")
+	}
+
+	if report.TotalEpisodes > 0 {
+		fmt.Fprintf(w, "Episodes: found %d of %d\n", report.FoundEpisodes, report.TotalEpisodes)
+	} else if report.FoundEpisodes > 0 {
+		fmt.Fprintf(w, "Episodes: found %d\n", report.FoundEpisodes)
+	}
+
+	if len(report.Matches) > 0 {
+		fmt.Fprintln(w, "Matches:")
+		for _, m := range report.Matches {
+			fmt.Fprintf(w, "  %s %s (%d bytes)\n", episodeTag(m), m.Path, m.Size)
+		}
+	}
+
+	if len(report.Rejections) > 0 {
+		fmt.Fprintln(w, "Rejections:")
+		for _, r := range report.Rejections {
+			candidate := r.Candidate
+			if r.PackFile != "" {
+				candidate = fmt.Sprintf("%s => %s", r.Candidate, r.PackFile)
+			}
+			fmt.Fprintf(w, "  %s: %s (%v => %v)\n", candidate, r.Reason, r.RejectValueA, r.RejectValueB)
+		}
+	}
+
+	if len(report.Links) > 0 {
+		fmt.Fprintln(w, "Links:")
+		for _, l := range report.Links {
+			fmt.Fprintf(w, "  %s => %s (%s)\n", l.Source, l.Target, l.Type)
+		}
+	}
+
+	if report.ID > 0 {
+		fmt.Fprintf(w, "For more details take a look at the history entry %d or the logs!\n", report.ID)
+	}
+}
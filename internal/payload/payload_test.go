@@ -0,0 +1,46 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package payload
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_printReport(t *testing.T) {
+	report := domain.NewHistory(domain.HistoryActionPack)
+	report.ID = 7
+	report.DryRun = true
+	report.FoundEpisodes = 1
+	report.TotalEpisodes = 2
+	report.AddMatch(domain.HistoryMatch{Season: 1, Episode: 1, Path: "/data/Series.Title.S01E01.mkv", Size: 1000})
+	report.AddMatch(domain.HistoryMatch{Season: 1, Episode: 3, Episodes: []int{3, 4}, Path: "/data/Series.Title.S01E03E04.mkv", Size: 2000})
+	report.AddRejection("Series.Title.S01E02.1080p.WEB-DL.H.264-OtherGrp", "", domain.CompareInfo{
+		StatusCode:   domain.StatusRlsGrpMismatch,
+		RejectValueA: "RlsGrp",
+		RejectValueB: "OtherGrp",
+	})
+	report.AddLink("/data/Series.Title.S01E01.mkv", "/pre-import/Series.Title.S01/Series.Title.S01E01.mkv", domain.LinkTypeHardlink)
+	report.Finish(domain.StatusSuccessfulHardlink, nil)
+
+	var buf bytes.Buffer
+	printReport(&buf, report)
+
+	assert.Equal(t, `Status: successful match (250)
+Dry run: no hardlinks were created
+Episodes: found 1 of 2
+Matches:
+  S01E01 /data/Series.Title.S01E01.mkv (1000 bytes)
+  S01E03E04 /data/Series.Title.S01E03E04.mkv (2000 bytes)
+Rejections:
+  Series.Title.S01E02.1080p.WEB-DL.H.264-OtherGrp: release group did not match (RlsGrp => OtherGrp)
+Links:
+  /data/Series.Title.S01E01.mkv => /pre-import/Series.Title.S01/Series.Title.S01E01.mkv (hardlink)
+For more details take a look at the history entry 7 or the logs!
+`, buf.String())
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/utils"
+
+	"github.com/moistari/rls"
+)
+
+var (
+	reSeasonRange = regexp.MustCompile(`(?i)\bS(\d{1,4})[ ._]?-[ ._]?S?(\d{1,4})\b`)
+	reSeason      = regexp.MustCompile(`(?i)\bS(\d{1,4})\b`)
+	reSeasonTags  = regexp.MustCompile(`(?i)\bS\d{1,4}(?:[ ._]?-[ ._]?S?\d{1,4})?(?:[ ._]S\d{1,4}(?:[ ._]?-[ ._]?S?\d{1,4})?)*\b`)
+	reComplete    = regexp.MustCompile(`(?i)[ ._-]Complete[ ._-](Series|Show)\b`)
+	reEpisodes    = regexp.MustCompile(`(?i)\bS(\d{1,4})((?:[ ._]?E\d{1,4})+)(?:[ ._]?-[ ._]?E?(\d{1,4}))?\b`)
+	reEpisode     = regexp.MustCompile(`(?i)E(\d{1,4})`)
+)
+
+// Pack is a parsed release that can span multiple seasons, e.g. Show.S01-S03 or Show.Complete.Series.
+// Release.Series is the first season of the pack and 0 for complete series packs.
+type Pack struct {
+	rls.Release
+	// Seasons contains every season of the pack in ascending order, it's empty for complete series packs.
+	Seasons []int
+	// Complete is set for complete series packs, which contain every season of a show.
+	Complete bool
+	// Episodes contains every episode of an episode release in ascending order, e.g. 1, 2 and 3 for S01E01-E03.
+	Episodes []int
+	// AirDate is only set for episodes of daily shows without season and episode, e.g. Show.2024.10.17.
+	AirDate time.Time
+}
+
+// ParsePack parses the given release name and detects the seasons it contains.
+func ParsePack(name string) Pack {
+	if season, episodes, normalized := parseEpisodes(name); len(episodes) > 0 {
+		r := rls.ParseString(normalized)
+		r.Series = season
+		r.Episode = episodes[0]
+
+		return Pack{Release: r, Seasons: []int{season}, Episodes: episodes}
+	}
+
+	r := rls.ParseString(name)
+
+	var episodes []int
+	if r.Episode > 0 {
+		episodes = []int{r.Episode}
+	}
+
+	if seasons, loc := parseSeasons(name); len(seasons) > 0 {
+		// rls doesn't understand seasons like S2024, which are used by some daily shows
+		if r.Series != seasons[0] {
+			r = rls.ParseString(name[:loc[0]] + "S01" + name[loc[1]:])
+			r.Series = seasons[0]
+		}
+
+		return Pack{Release: r, Seasons: seasons, Episodes: episodes}
+	}
+
+	if r.Series > 0 {
+		return Pack{Release: r, Seasons: []int{r.Series}, Episodes: episodes}
+	}
+
+	// episodes in the season folders of a pack can come without a season
+	if r.Episode > 0 {
+		return Pack{Release: r, Episodes: episodes}
+	}
+
+	// episodes of daily shows only have an air date, which rls parses as the year of the release
+	if r.Month > 0 && r.Day > 0 {
+		airDate := time.Date(r.Year, time.Month(r.Month), r.Day, 0, 0, 0, 0, time.UTC)
+		r.Year, r.Month, r.Day = 0, 0, 0
+
+		return Pack{Release: r, AirDate: airDate}
+	}
+
+	// releases without any season information are only treated as complete series packs if they say so,
+	// otherwise they are most likely a movie
+	if !reComplete.MatchString(name) {
+		return Pack{Release: r}
+	}
+
+	r = rls.ParseString(reComplete.ReplaceAllString(name, ""))
+	r.Type = rls.Series
+	r.Series = 0
+
+	return Pack{Release: r, Complete: true}
+}
+
+// parseEpisodes returns the season and episodes of an episode tag like S01E01, S01E01E02 or S01E01-E03 in the
+// given release name, together with the name using a single episode tag, since rls can't parse most of them.
+func parseEpisodes(name string) (int, []int, string) {
+	m := reEpisodes.FindStringSubmatchIndex(name)
+	if m == nil {
+		return 0, nil, name
+	}
+
+	season, _ := strconv.Atoi(name[m[2]:m[3]])
+
+	episodes := make([]int, 0)
+	for _, e := range reEpisode.FindAllStringSubmatch(name[m[4]:m[5]], -1) {
+		episode, _ := strconv.Atoi(e[1])
+		episodes = append(episodes, episode)
+	}
+
+	// a range like E01-E03 covers all episodes in between
+	if m[6] != -1 {
+		last, _ := strconv.Atoi(name[m[6]:m[7]])
+		for episode := episodes[len(episodes)-1] + 1; episode <= last; episode++ {
+			episodes = append(episodes, episode)
+		}
+	}
+
+	slices.Sort(episodes)
+	episodes = slices.Compact(episodes)
+
+	// the season and episode are set after parsing, so seasons like S2024 don't break the parsed title
+	return season, episodes, name[:m[0]] + "S01E01" + name[m[1]:]
+}
+
+// parseSeasons returns the seasons of the first run of standalone season tags, e.g. S01-S03 or S01.S02, in the
+// given release name, together with its location. Season tags that are part of an episode tag like S01E01 are
+// ignored, just like the group of the release, which can look like a season tag as well, e.g. Show.S01.1080p-S2.
+func parseSeasons(name string) ([]int, []int) {
+	loc := firstSeasonTags(name)
+	if loc == nil {
+		return nil, nil
+	}
+
+	tags := name[loc[0]:loc[1]]
+	seasons := make([]int, 0)
+
+	for _, m := range reSeasonRange.FindAllStringSubmatch(tags, -1) {
+		first, _ := strconv.Atoi(m[1])
+		last, _ := strconv.Atoi(m[2])
+
+		for s := first; s <= last; s++ {
+			seasons = append(seasons, s)
+		}
+	}
+
+	for _, m := range reSeason.FindAllStringSubmatch(reSeasonRange.ReplaceAllString(tags, ""), -1) {
+		s, _ := strconv.Atoi(m[1])
+		seasons = append(seasons, s)
+	}
+
+	slices.Sort(seasons)
+	return slices.Compact(seasons), loc
+}
+
+// firstSeasonTags returns the location of the first run of season tags in the given release name that isn't the
+// group of the release, i.e. a single tag after the last dash at the end of the name.
+func firstSeasonTags(name string) []int {
+	for _, loc := range reSeasonTags.FindAllStringIndex(name, -1) {
+		if loc[1] == len(name) && loc[0] > 0 && name[loc[0]-1] == '-' && !reSeasonRange.MatchString(name[loc[0]:]) {
+			continue
+		}
+
+		return loc
+	}
+
+	return nil
+}
+
+// MultiEpisode reports whether the release contains more than a single episode.
+func (p Pack) MultiEpisode() bool {
+	return len(p.Episodes) > 1
+}
+
+// MultiSeason reports whether the pack contains more than a single season.
+func (p Pack) MultiSeason() bool {
+	return p.Complete || len(p.Seasons) > 1
+}
+
+// HasSeason reports whether the given season is part of the pack.
+func (p Pack) HasSeason(season int) bool {
+	if p.Complete {
+		return season > 0
+	}
+
+	return slices.Contains(p.Seasons, season)
+}
+
+// Includes reports whether the client release belongs to the pack. Episodes belong to it if their season
+// is part of the pack, other packs only if they cover all seasons of it, so they are detected as already
+// being in the client.
+func (p Pack) Includes(clientPack Pack) bool {
+	if clientPack.Episode != 0 {
+		return p.HasSeason(clientPack.Series)
+	}
+
+	if clientPack.Complete {
+		return true
+	}
+
+	if p.Complete || len(clientPack.Seasons) == 0 {
+		return false
+	}
+
+	for _, season := range p.Seasons {
+		if !clientPack.HasSeason(season) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Resolve converts the alternative numbering of a release without a season to season and episode numbers,
+// i.e. the air date of daily shows and the absolute episode numbers of anime like Show - 13. If the release is
+// known to belong to a season, e.g. because of the folder it's in, the alternative numbering is only used if it
+// resolves to that season, otherwise the episode numbers are treated as relative to it. Releases that contain a
+// season are returned unchanged.
+func (p Pack) Resolve(numbers *utils.EpisodeNumbers, season int) Pack {
+	if p.Series != 0 || p.Special() {
+		return p
+	}
+
+	if numbers != nil {
+		var resolved []utils.SeasonEpisode
+		if !p.AirDate.IsZero() {
+			if se, ok := numbers.AirDate[p.AirDate.Format(time.DateOnly)]; ok {
+				resolved = []utils.SeasonEpisode{se}
+			}
+		} else {
+			resolved, _ = resolveEpisodes(numbers.Absolute, p.Episodes)
+		}
+
+		if len(resolved) > 0 && (season == 0 || resolved[0].Season == season) {
+			p.Series = resolved[0].Season
+			p.Seasons = []int{p.Series}
+			p.Episodes = make([]int, 0, len(resolved))
+			for _, r := range resolved {
+				p.Episodes = append(p.Episodes, r.Episode)
+			}
+			p.Episode = p.Episodes[0]
+
+			return p
+		}
+	}
+
+	if season != 0 && len(p.Episodes) > 0 {
+		p.Series = season
+		p.Seasons = []int{season}
+	}
+
+	return p
+}
+
+// NeedsResolve reports whether the release has no season, but an alternative numbering that Resolve can
+// convert.
+func (p Pack) NeedsResolve() bool {
+	return p.Series == 0 && !p.Special() && (len(p.Episodes) > 0 || !p.AirDate.IsZero())
+}
+
+// Special reports whether the release is a special, i.e. an episode of season 0 like S00E01.
+func (p Pack) Special() bool {
+	return p.Series == 0 && len(p.Episodes) > 0 && slices.Equal(p.Seasons, []int{0})
+}
+
+// resolveEpisodes looks up all absolute episodes, which need to belong to the same season.
+func resolveEpisodes(absolute map[int]utils.SeasonEpisode, episodes []int) ([]utils.SeasonEpisode, bool) {
+	resolved := make([]utils.SeasonEpisode, 0, len(episodes))
+
+	for _, episode := range episodes {
+		r, ok := absolute[episode]
+		if !ok || (len(resolved) > 0 && r.Season != resolved[0].Season) {
+			return nil, false
+		}
+		resolved = append(resolved, r)
+	}
+
+	return resolved, len(resolved) > 0
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nuxencs/seasonpackarr/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParsePack(t *testing.T) {
+	tests := []struct {
+		name         string
+		packName     string
+		wantTitle    string
+		wantSeasons  []int
+		wantComplete bool
+		wantMulti    bool
+		wantEpisodes []int
+		wantAirDate  string
+	}{
+		{
+			name:        "single_season",
+			packName:    "Series Title S02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp",
+			wantTitle:   "Series Title",
+			wantSeasons: []int{2},
+		},
+		{
+			name:        "season_range",
+			packName:    "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:   "Series Title",
+			wantSeasons: []int{1, 2, 3},
+			wantMulti:   true,
+		},
+		{
+			name:        "season_range_short",
+			packName:    "Series.Title.2022.S01-03.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:   "Series Title",
+			wantSeasons: []int{1, 2, 3},
+			wantMulti:   true,
+		},
+		{
+			name:        "season_list",
+			packName:    "Series.Title.S01.S02.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:   "Series Title",
+			wantSeasons: []int{1, 2},
+			wantMulti:   true,
+		},
+		{
+			name:        "season_like_group",
+			packName:    "Show.S01.1080p.BluRay.x264-S2",
+			wantTitle:   "Show",
+			wantSeasons: []int{1},
+		},
+		{
+			name:        "season_like_group_daily",
+			packName:    "Show.S2024.1080p.WEB-DL.H.264-S03",
+			wantTitle:   "Show",
+			wantSeasons: []int{2024},
+		},
+		{
+			name:         "complete_series",
+			packName:     "Series.Title.Complete.Series.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:    "Series Title",
+			wantComplete: true,
+			wantMulti:    true,
+		},
+		{
+			name:         "episode",
+			packName:     "Series.Title.S02E05.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:    "Series Title",
+			wantSeasons:  []int{2},
+			wantEpisodes: []int{5},
+		},
+		{
+			name:         "double_episode",
+			packName:     "Series.Title.S02E05E06.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:    "Series Title",
+			wantSeasons:  []int{2},
+			wantEpisodes: []int{5, 6},
+		},
+		{
+			name:         "episode_range",
+			packName:     "Series.Title.S02E05-E07.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			wantTitle:    "Series Title",
+			wantSeasons:  []int{2},
+			wantEpisodes: []int{5, 6, 7},
+		},
+		{
+			name:         "episode_range_short",
+			packName:     "Series Title S02E05-07 1080p WEB-DL H 264-RlsGrp",
+			wantTitle:    "Series Title",
+			wantSeasons:  []int{2},
+			wantEpisodes: []int{5, 6, 7},
+		},
+		{
+			name:         "special",
+			packName:     "Series.Title.S00E03.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle:    "Series Title",
+			wantSeasons:  []int{0},
+			wantEpisodes: []int{3},
+		},
+		{
+			name:        "daily_season",
+			packName:    "Show.Title.S2024.1080p.WEB.H264-RlsGrp",
+			wantTitle:   "Show Title",
+			wantSeasons: []int{2024},
+		},
+		{
+			name:         "daily_season_episode",
+			packName:     "Show.Title.S2024E150.1080p.WEB.H264-RlsGrp",
+			wantTitle:    "Show Title",
+			wantSeasons:  []int{2024},
+			wantEpisodes: []int{150},
+		},
+		{
+			name:        "daily_episode",
+			packName:    "Show.Title.2024.10.17.Guest.Name.1080p.WEB.H264-RlsGrp",
+			wantTitle:   "Show Title",
+			wantAirDate: "2024-10-17",
+		},
+		{
+			name:      "movie",
+			packName:  "Movie.Title.2022.1080p.WEB-DL.H.264-RlsGrp",
+			wantTitle: "Movie Title",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsePack(tt.packName)
+			assert.Equal(t, tt.wantTitle, got.Title)
+			assert.ElementsMatch(t, tt.wantSeasons, got.Seasons)
+			assert.Equal(t, tt.wantComplete, got.Complete)
+			assert.Equal(t, tt.wantMulti, got.MultiSeason())
+			assert.Equal(t, tt.wantEpisodes, got.Episodes)
+			if tt.wantAirDate != "" {
+				assert.Equal(t, tt.wantAirDate, got.AirDate.Format(time.DateOnly))
+				assert.Zero(t, got.Year)
+			} else {
+				assert.True(t, got.AirDate.IsZero())
+			}
+		})
+	}
+}
+
+func Test_Pack_Includes(t *testing.T) {
+	tests := []struct {
+		name       string
+		packName   string
+		clientName string
+		want       bool
+	}{
+		{
+			name:       "episode_of_season",
+			packName:   "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			clientName: "Series.Title.S02E01.1080p.WEB-DL.H.264-RlsGrp",
+			want:       true,
+		},
+		{
+			name:       "episode_of_other_season",
+			packName:   "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			clientName: "Series.Title.S04E01.1080p.WEB-DL.H.264-RlsGrp",
+			want:       false,
+		},
+		{
+			name:       "episode_of_complete_series",
+			packName:   "Series.Title.Complete.Series.1080p.WEB-DL.H.264-RlsGrp",
+			clientName: "Series.Title.S07E10.1080p.WEB-DL.H.264-RlsGrp",
+			want:       true,
+		},
+		{
+			name:       "same_pack",
+			packName:   "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			clientName: "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			want:       true,
+		},
+		{
+			name:       "covering_pack",
+			packName:   "Series.Title.S02.1080p.WEB-DL.H.264-RlsGrp",
+			clientName: "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			want:       true,
+		},
+		{
+			name:       "partial_pack",
+			packName:   "Series.Title.S01-S03.1080p.WEB-DL.H.264-RlsGrp",
+			clientName: "Series.Title.S02.1080p.WEB-DL.H.264-RlsGrp",
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParsePack(tt.packName).Includes(ParsePack(tt.clientName)))
+		})
+	}
+}
+
+func Test_Pack_Resolve(t *testing.T) {
+	numbers := &utils.EpisodeNumbers{
+		Absolute: map[int]utils.SeasonEpisode{
+			1:  {Season: 1, Episode: 1},
+			12: {Season: 1, Episode: 12},
+			13: {Season: 2, Episode: 1},
+			14: {Season: 2, Episode: 2},
+		},
+		AirDate: map[string]utils.SeasonEpisode{
+			"2024-10-17": {Season: 2024, Episode: 150},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		epName       string
+		season       int
+		wantSeason   int
+		wantEpisodes []int
+	}{
+		{
+			name:         "absolute",
+			epName:       "Series Title - 13 [1080p].mkv",
+			wantSeason:   2,
+			wantEpisodes: []int{1},
+		},
+		{
+			name:         "season_and_episode",
+			epName:       "Series.Title.S01E12.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			wantSeason:   1,
+			wantEpisodes: []int{12},
+		},
+		{
+			name:         "absolute_in_season_folder",
+			epName:       "Series Title - 14 [1080p].mkv",
+			season:       2,
+			wantSeason:   2,
+			wantEpisodes: []int{2},
+		},
+		{
+			name:         "relative_in_season_folder",
+			epName:       "Series.Title.E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			season:       2,
+			wantSeason:   2,
+			wantEpisodes: []int{1},
+		},
+		{
+			name:         "air_date",
+			epName:       "Show.Title.2024.10.17.Guest.Name.1080p.WEB.H264-RlsGrp.mkv",
+			wantSeason:   2024,
+			wantEpisodes: []int{150},
+		},
+		{
+			name:         "special",
+			epName:       "Series.Title.S00E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			wantSeason:   0,
+			wantEpisodes: []int{1},
+		},
+		{
+			name:         "unknown_absolute",
+			epName:       "Series Title - 99 [1080p].mkv",
+			wantSeason:   0,
+			wantEpisodes: []int{99},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsePack(tt.epName).Resolve(numbers, tt.season)
+			assert.Equal(t, tt.wantSeason, got.Series)
+			assert.Equal(t, tt.wantEpisodes, got.Episodes)
+		})
+	}
+}
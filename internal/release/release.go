@@ -5,6 +5,7 @@ package release
 
 import (
 	"path/filepath"
+	"slices"
 
 	"github.com/nuxencs/seasonpackarr/internal/domain"
 	"github.com/nuxencs/seasonpackarr/internal/utils"
@@ -103,7 +104,10 @@ func compareReleases(requestRls, clientRls rls.Release, fuzzyMatching domain.Fuz
 	return domain.CompareInfo{StatusCode: domain.StatusSuccessfulMatch}
 }
 
-func MatchEpToSeasonPackEp(clientEpPath string, clientEpSize int64, torrentEpPath string, torrentEpSize int64) (string, domain.CompareInfo) {
+// MatchEpToSeasonPackEp checks if the episode in the client is the same as the episode in the pack. The
+// episode numbers are used to resolve releases without a season, they can be nil if not needed.
+func MatchEpToSeasonPackEp(clientEpPath string, clientEpSize int64, torrentEpPath string, torrentEpSize int64,
+	numbers *utils.EpisodeNumbers) (string, domain.CompareInfo) {
 	if clientEpSize != torrentEpSize {
 		return "", domain.CompareInfo{
 			StatusCode:   domain.StatusSizeMismatch,
@@ -112,8 +116,18 @@ func MatchEpToSeasonPackEp(clientEpPath string, clientEpSize int64, torrentEpPat
 		}
 	}
 
-	clientEpRls := rls.ParseString(filepath.Base(clientEpPath))
-	torrentEpRls := rls.ParseString(filepath.Base(torrentEpPath))
+	clientEpRls := ParsePack(filepath.Base(clientEpPath)).Resolve(numbers, 0)
+	torrentEpRls := ParsePack(filepath.Base(torrentEpPath))
+
+	// episodes in the season folders of multi season packs don't always contain the season themselves
+	if torrentEpRls.Series == 0 {
+		folderSeason := 0
+		if dir := filepath.Dir(torrentEpPath); dir != "." {
+			folderSeason = ParsePack(filepath.Base(dir)).Series
+		}
+
+		torrentEpRls = torrentEpRls.Resolve(numbers, folderSeason)
+	}
 
 	switch {
 	case clientEpRls.Series != torrentEpRls.Series:
@@ -122,11 +136,11 @@ func MatchEpToSeasonPackEp(clientEpPath string, clientEpSize int64, torrentEpPat
 			RejectValueA: clientEpRls.Series,
 			RejectValueB: torrentEpRls.Series,
 		}
-	case clientEpRls.Episode != torrentEpRls.Episode:
+	case !slices.Equal(clientEpRls.Episodes, torrentEpRls.Episodes):
 		return "", domain.CompareInfo{
 			StatusCode:   domain.StatusEpisodeMismatch,
-			RejectValueA: clientEpRls.Episode,
-			RejectValueB: torrentEpRls.Episode,
+			RejectValueA: episodesValue(clientEpRls),
+			RejectValueB: episodesValue(torrentEpRls),
 		}
 	case clientEpRls.Resolution != torrentEpRls.Resolution:
 		return "", domain.CompareInfo{
@@ -145,6 +159,15 @@ func MatchEpToSeasonPackEp(clientEpPath string, clientEpSize int64, torrentEpPat
 	return torrentEpPath, domain.CompareInfo{}
 }
 
+// episodesValue returns the episode of a single episode release and all episodes of a multi episode release.
+func episodesValue(p Pack) any {
+	if p.MultiEpisode() {
+		return p.Episodes
+	}
+
+	return p.Episode
+}
+
 func PercentOfTotalEpisodes(totalEps int, foundEps int) float32 {
 	if totalEps == 0 {
 		return 0
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/internal/utils"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,6 +15,18 @@ func Test_MatchEpToSeasonPackEp(t *testing.T) {
 		clientEpSize  int64
 		torrentEpPath string
 		torrentEpSize int64
+		numbers       *utils.EpisodeNumbers
+	}
+
+	numbers := &utils.EpisodeNumbers{
+		Absolute: map[int]utils.SeasonEpisode{
+			12: {Season: 1, Episode: 12},
+			13: {Season: 2, Episode: 1},
+			14: {Season: 2, Episode: 2},
+		},
+		AirDate: map[string]utils.SeasonEpisode{
+			"2024-10-17": {Season: 2024, Episode: 150},
+		},
 	}
 
 	type compare struct {
@@ -176,10 +189,130 @@ func Test_MatchEpToSeasonPackEp(t *testing.T) {
 				info: domain.CompareInfo{},
 			},
 		},
+		{
+			name: "multi_episode",
+			args: args{
+				clientEpPath:  "Series Title 2022 S02E01E02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Series Title 2022 S02E01-E02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+			},
+			want: compare{
+				path: "Series Title 2022 S02E01-E02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				info: domain.CompareInfo{},
+			},
+		},
+		{
+			name: "multi_episode_mismatch",
+			args: args{
+				clientEpPath:  "Series Title 2022 S02E01E02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Series Title 2022 S02E01 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+			},
+			want: compare{
+				path: "",
+				info: domain.CompareInfo{
+					StatusCode:   domain.StatusEpisodeMismatch,
+					RejectValueA: []int{1, 2},
+					RejectValueB: 1,
+				},
+			},
+		},
+		{
+			name: "absolute_in_torrent",
+			args: args{
+				clientEpPath:  "Series Title S02E01 1080p WEB-DL H.264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Series Title - 13 1080p WEB-DL H.264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+				numbers:       numbers,
+			},
+			want: compare{
+				path: "Series Title - 13 1080p WEB-DL H.264-RlsGrp.mkv",
+				info: domain.CompareInfo{},
+			},
+		},
+		{
+			name: "absolute_in_client",
+			args: args{
+				clientEpPath:  "Series Title - 14 1080p WEB-DL H.264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Series Title S02/Series Title S02E02 1080p WEB-DL H.264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+				numbers:       numbers,
+			},
+			want: compare{
+				path: "Series Title S02/Series Title S02E02 1080p WEB-DL H.264-RlsGrp.mkv",
+				info: domain.CompareInfo{},
+			},
+		},
+		{
+			name: "absolute_without_numbering",
+			args: args{
+				clientEpPath:  "Series Title S02E01 1080p WEB-DL H.264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Series Title - 13 1080p WEB-DL H.264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+			},
+			want: compare{
+				path: "",
+				info: domain.CompareInfo{
+					StatusCode:   domain.StatusSeasonMismatch,
+					RejectValueA: 2,
+					RejectValueB: 0,
+				},
+			},
+		},
+		{
+			name: "air_date",
+			args: args{
+				clientEpPath:  "Show Title 2024 10 17 Guest Name 1080p WEB H264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Show Title S2024 1080p WEB H264-RlsGrp/Show Title 2024 10 17 Guest Name 1080p WEB H264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+				numbers:       numbers,
+			},
+			want: compare{
+				path: "Show Title S2024 1080p WEB H264-RlsGrp/Show Title 2024 10 17 Guest Name 1080p WEB H264-RlsGrp.mkv",
+				info: domain.CompareInfo{},
+			},
+		},
+		{
+			name: "air_date_mismatch",
+			args: args{
+				clientEpPath:  "Show Title 2024 10 17 Guest Name 1080p WEB H264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Show Title 2024 10 16 Other Guest 1080p WEB H264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+				numbers:       numbers,
+			},
+			want: compare{
+				path: "",
+				info: domain.CompareInfo{
+					StatusCode:   domain.StatusSeasonMismatch,
+					RejectValueA: 2024,
+					RejectValueB: 0,
+				},
+			},
+		},
+		{
+			name: "season_from_folder",
+			args: args{
+				clientEpPath:  "Series Title 2022 S02E01 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				clientEpSize:  2316560346,
+				torrentEpPath: "Series Title 2022 S02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp/Series Title 2022 E01 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				torrentEpSize: 2316560346,
+			},
+			want: compare{
+				path: "Series Title 2022 S02 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp/Series Title 2022 E01 1080p ATVP WEB-DL DDP 5.1 Atmos H.264-RlsGrp.mkv",
+				info: domain.CompareInfo{},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotPath, gotInfo := MatchEpToSeasonPackEp(tt.args.clientEpPath, tt.args.clientEpSize, tt.args.torrentEpPath, tt.args.torrentEpSize)
+			gotPath, gotInfo := MatchEpToSeasonPackEp(tt.args.clientEpPath, tt.args.clientEpSize, tt.args.torrentEpPath, tt.args.torrentEpSize, tt.args.numbers)
 
 			got := compare{
 				path: gotPath,
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package torrents
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+var (
+	// reSampleFolder matches the folders that releases put their samples in.
+	reSampleFolder = regexp.MustCompile(`(?i)^samples?$`)
+	// reSampleFile matches sample files like rlsgrp-show-s01e01-sample.mkv or sample-show.s01e01.mkv.
+	reSampleFile = regexp.MustCompile(`(?i)(^|[ ._-])sample$|^sample[ ._-]`)
+)
+
+// Exclusions decides which files of a torrent are junk, e.g. samples, and aren't treated as episodes.
+type Exclusions struct {
+	samples  bool
+	minSize  int64
+	patterns []*regexp.Regexp
+}
+
+// NewExclusions compiles the configured exclusion rules.
+func NewExclusions(cfg domain.Exclusions) (*Exclusions, error) {
+	e := &Exclusions{
+		samples:  cfg.Samples,
+		minSize:  int64(cfg.MinSize) * 1024 * 1024,
+		patterns: make([]*regexp.Regexp, 0, len(cfg.Patterns)),
+	}
+
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compile exclusion pattern %q", pattern)
+		}
+		e.patterns = append(e.patterns, re)
+	}
+
+	return e, nil
+}
+
+// Excluded reports whether the file at path with the given size is excluded.
+func (e *Exclusions) Excluded(path string, size int64) bool {
+	if size < e.minSize {
+		return true
+	}
+
+	if e.samples && IsSample(path) {
+		return true
+	}
+
+	for _, re := range e.patterns {
+		if re.MatchString(filepath.ToSlash(path)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSample reports whether the file at path is a sample, i.e. it's in a sample folder or named like one.
+func IsSample(path string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if reSampleFolder.MatchString(dir) {
+			return true
+		}
+	}
+
+	name := filepath.Base(path)
+	return reSampleFile.MatchString(strings.TrimSuffix(name, filepath.Ext(name)))
+}
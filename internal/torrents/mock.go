@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/anacrolix/torrent/bencode"
@@ -12,7 +13,10 @@ import (
 	regexp "github.com/dlclark/regexp2"
 )
 
-var seasonRegex = regexp.MustCompile(`\bS\d+\b(?!E\d+\b)`, regexp.IgnoreCase)
+var (
+	seasonRegex      = regexp.MustCompile(`\bS\d+\b(?!E\d+\b)`, regexp.IgnoreCase)
+	seasonRangeRegex = regexp.MustCompile(`\bS(\d+)-S(\d+)\b`, regexp.IgnoreCase)
+)
 
 func mockEpisodes(dir string, numEpisodes int) error {
 	match, err := seasonRegex.FindStringMatch(filepath.Base(dir))
@@ -39,6 +43,31 @@ func mockEpisodes(dir string, numEpisodes int) error {
 	return nil
 }
 
+// mockSeasons creates a folder with numEpisodes episodes for every season of a multi season pack.
+func mockSeasons(dir string, numEpisodes int) (bool, error) {
+	match, err := seasonRangeRegex.FindStringMatch(filepath.Base(dir))
+	if err != nil || match == nil {
+		return false, err
+	}
+
+	first, _ := strconv.Atoi(match.GroupByNumber(1).String())
+	last, _ := strconv.Atoi(match.GroupByNumber(2).String())
+
+	for season := first; season <= last; season++ {
+		seasonDir := filepath.Join(dir, strings.Replace(filepath.Base(dir), match.String(), fmt.Sprintf("S%02d", season), 1))
+
+		if err = os.Mkdir(seasonDir, os.ModePerm); err != nil {
+			return true, err
+		}
+
+		if err = mockEpisodes(seasonDir, numEpisodes); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
 func torrentFromFolder(folderPath string) ([]byte, error) {
 	mi := metainfo.MetaInfo{
 		AnnounceList: [][]string{},
@@ -77,9 +106,16 @@ func TorrentFromRls(rlsName string, numEpisodes int) ([]byte, error) {
 	}
 	defer os.RemoveAll(tempDirPath)
 
-	if err = mockEpisodes(tempDirPath, numEpisodes); err != nil {
+	multiSeason, err := mockSeasons(tempDirPath, numEpisodes)
+	if err != nil {
 		return nil, err
 	}
 
+	if !multiSeason {
+		if err = mockEpisodes(tempDirPath, numEpisodes); err != nil {
+			return nil, err
+		}
+	}
+
 	return torrentFromFolder(tempDirPath)
 }
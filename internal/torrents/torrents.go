@@ -8,11 +8,18 @@ import (
 	"cmp"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strings"
+
+	"github.com/nuxencs/seasonpackarr/internal/utils"
 
 	"github.com/anacrolix/torrent/metainfo"
 )
 
+// reExtras matches the folders that season packs put their bonus material in.
+var reExtras = regexp.MustCompile(`(?i)^(extras?|featurettes?|bonus|behind[ ._-]the[ ._-]scenes|deleted[ ._-]scenes|interviews|trailers|specials)$`)
+
 type Episode struct {
 	Path string
 	Size int64
@@ -27,18 +34,30 @@ func ParseInfoFromTorrentBytes(torrentBytes []byte) (metainfo.Info, error) {
 	return metaInfo.UnmarshalInfo()
 }
 
-func GetEpisodesFromTorrentInfo(info metainfo.Info) ([]Episode, error) {
+// GetEpisodesFromTorrentInfo returns all files of the torrent with one of the given video extensions,
+// sorted by path.
+func GetEpisodesFromTorrentInfo(info metainfo.Info, extensions []string) ([]Episode, error) {
 	if !info.IsDir() {
 		return []Episode{}, fmt.Errorf("not a directory")
 	}
 
+	episodes := GetFilesFromTorrentInfo(info, extensions)
+	if len(episodes) == 0 {
+		return []Episode{}, fmt.Errorf("no video files found")
+	}
+
+	return episodes, nil
+}
+
+// GetFilesFromTorrentInfo returns all files of the torrent with one of the given extensions, sorted by path.
+func GetFilesFromTorrentInfo(info metainfo.Info, extensions []string) []Episode {
 	files := info.UpvertedFiles()
 	episodes := make([]Episode, 0, len(files))
 
 	for _, file := range files {
 		path := file.DisplayPath(&info)
 
-		if filepath.Ext(path) != ".mkv" {
+		if !utils.HasExtension(path, extensions) {
 			continue
 		}
 
@@ -48,15 +67,23 @@ func GetEpisodesFromTorrentInfo(info metainfo.Info) ([]Episode, error) {
 		})
 	}
 
-	if len(episodes) == 0 {
-		return []Episode{}, fmt.Errorf("no .mkv files found")
-	}
-
 	if len(episodes) > 1 {
 		slices.SortStableFunc(episodes, func(a, b Episode) int {
 			return cmp.Compare(a.Path, b.Path)
 		})
 	}
 
-	return episodes, nil
+	return episodes
+}
+
+// IsExtra reports whether the file at path is in one of the extras folders of a season pack, e.g. Extras or
+// Featurettes.
+func IsExtra(path string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if reExtras.MatchString(dir) {
+			return true
+		}
+	}
+
+	return false
 }
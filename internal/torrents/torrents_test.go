@@ -0,0 +1,127 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package torrents
+
+import (
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsExtra(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "episode",
+			path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			want: false,
+		},
+		{
+			name: "episode_in_season_folder",
+			path: "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			want: false,
+		},
+		{
+			name: "extras",
+			path: "Extras/Making.Of.mkv",
+			want: true,
+		},
+		{
+			name: "featurettes_in_season_folder",
+			path: "Season 1/Featurettes/Interview.mkv",
+			want: true,
+		},
+		{
+			name: "behind_the_scenes",
+			path: "Behind The Scenes/Part.1.mkv",
+			want: true,
+		},
+		{
+			name: "extras_in_name",
+			path: "Series.Title.S01E01.Extras.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsExtra(tt.path))
+		})
+	}
+}
+
+func Test_Exclusions(t *testing.T) {
+	tests := []struct {
+		name       string
+		exclusions domain.Exclusions
+		path       string
+		size       int64
+		want       bool
+	}{
+		{
+			name:       "episode",
+			exclusions: domain.Exclusions{Samples: true, MinSize: 50},
+			path:       "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			size:       1024 * 1024 * 1024,
+			want:       false,
+		},
+		{
+			name:       "sample_folder",
+			exclusions: domain.Exclusions{Samples: true},
+			path:       "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp/Sample/Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			size:       1024,
+			want:       true,
+		},
+		{
+			name:       "sample_file",
+			exclusions: domain.Exclusions{Samples: true},
+			path:       "rlsgrp-series.title.s01e01-sample.mkv",
+			size:       1024,
+			want:       true,
+		},
+		{
+			name:       "samples_disabled",
+			exclusions: domain.Exclusions{},
+			path:       "Sample/rlsgrp-series.title.s01e01-sample.mkv",
+			size:       1024,
+			want:       false,
+		},
+		{
+			name:       "title_containing_sample",
+			exclusions: domain.Exclusions{Samples: true},
+			path:       "The.Sample.Show.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			size:       1024,
+			want:       false,
+		},
+		{
+			name:       "below_min_size",
+			exclusions: domain.Exclusions{MinSize: 50},
+			path:       "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			size:       49 * 1024 * 1024,
+			want:       true,
+		},
+		{
+			name:       "pattern",
+			exclusions: domain.Exclusions{Patterns: []string{`(?i)\.trailer\.`}},
+			path:       "Trailers/Series.Title.Trailer.1080p.mkv",
+			size:       1024,
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewExclusions(tt.exclusions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, e.Excluded(tt.path, tt.size))
+		})
+	}
+
+	_, err := NewExclusions(domain.Exclusions{Patterns: []string{"("}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package utils
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// HasExtension reports whether the file at path has one of the given extensions, ignoring case.
+func HasExtension(path string, extensions []string) bool {
+	return slices.Contains(extensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// NormalizeExtensions returns the extensions in lower case with a leading dot, so "MKV" and ".mkv" are the same.
+func NormalizeExtensions(extensions []string) []string {
+	normalized := make([]string, 0, len(extensions))
+
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+
+		normalized = append(normalized, ext)
+	}
+
+	return normalized
+}
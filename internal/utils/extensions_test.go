@@ -0,0 +1,50 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HasExtension(t *testing.T) {
+	extensions := []string{".mkv", ".mp4"}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "mkv",
+			path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv",
+			want: true,
+		},
+		{
+			name: "upper_case",
+			path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.MP4",
+			want: true,
+		},
+		{
+			name: "other_extension",
+			path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.srt",
+			want: false,
+		},
+		{
+			name: "no_extension",
+			path: "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasExtension(tt.path, extensions))
+		})
+	}
+}
+
+func Test_NormalizeExtensions(t *testing.T) {
+	assert.Equal(t, []string{".mkv", ".mp4", ".srt"}, NormalizeExtensions([]string{"mkv", ".MP4", " .srt ", ""}))
+}
@@ -11,8 +11,10 @@ import (
 	"github.com/moistari/rls"
 )
 
-func GetFormattedTitle(r rls.Release) string {
-	s := fmt.Sprintf("%s%d%d", rls.MustNormalize(r.Title), r.Year, r.Series)
+// GetFormattedShowTitle returns the normalized title and year of the release, so it's the same for all
+// seasons of a show.
+func GetFormattedShowTitle(r rls.Release) string {
+	s := fmt.Sprintf("%s%d", rls.MustNormalize(r.Title), r.Year)
 
 	return s
 }
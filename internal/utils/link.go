@@ -0,0 +1,195 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package utils
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+	"github.com/nuxencs/seasonpackarr/pkg/errors"
+)
+
+var (
+	ErrCrossDevice        = errors.Sentinel("source and target are on different filesystems")
+	ErrReflinkUnsupported = errors.Sentinel("reflinks are not supported on this platform")
+)
+
+// CreateLink links srcPath to trgPath with the first of the given link types that succeeds and returns
+// the link type that was used. The link types are tried in order, so e.g. hardlink, reflink, copy falls back
+// to reflinks and copies if the target is on a different filesystem.
+func CreateLink(srcPath, trgPath string, linkTypes []string) (string, error) {
+	if len(linkTypes) == 0 {
+		linkTypes = []string{domain.LinkTypeHardlink}
+	}
+
+	// create the target directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(trgPath), 0755); err != nil {
+		return "", err
+	}
+
+	errs := make([]string, 0, len(linkTypes))
+
+	for _, linkType := range linkTypes {
+		err := createLink(srcPath, trgPath, linkType)
+		if err == nil {
+			return linkType, nil
+		}
+
+		// no other link type can succeed if the target already exists
+		if errors.Is(err, fs.ErrExist) {
+			return "", err
+		}
+
+		if errors.Is(err, syscall.EXDEV) {
+			err = errors.Wrap(ErrCrossDevice, "%s %s to %s", linkType, srcPath, trgPath)
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	return "", errors.New("all link types failed: %s", strings.Join(errs, "; "))
+}
+
+func createLink(srcPath, trgPath, linkType string) error {
+	switch linkType {
+	case domain.LinkTypeHardlink:
+		return os.Link(srcPath, trgPath)
+	case domain.LinkTypeReflink:
+		return reflink(srcPath, trgPath)
+	case domain.LinkTypeSymlink:
+		absSrcPath, err := filepath.Abs(srcPath)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(absSrcPath, trgPath)
+	case domain.LinkTypeCopy:
+		return copyFile(srcPath, trgPath)
+	default:
+		return errors.New("unknown link type: %s", linkType)
+	}
+}
+
+// copyFile copies srcPath to trgPath, a partially written target is removed on failure.
+func copyFile(srcPath, trgPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	trg, err := os.OpenFile(trgPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(trg, src); err != nil {
+		trg.Close()
+		os.Remove(trgPath)
+		return err
+	}
+
+	if err = trg.Close(); err != nil {
+		os.Remove(trgPath)
+		return err
+	}
+
+	return nil
+}
+
+// LinkTransaction creates links and keeps track of them and the directories it had to create, so a
+// partially linked season pack can be removed again.
+type LinkTransaction struct {
+	linkTypes []string
+	links     []string
+	dirs      []string
+}
+
+func NewLinkTransaction(linkTypes []string) *LinkTransaction {
+	return &LinkTransaction{linkTypes: linkTypes}
+}
+
+// Link creates a link like CreateLink and records it for Rollback.
+func (tx *LinkTransaction) Link(srcPath, trgPath string) (string, error) {
+	dirs := missingDirs(filepath.Dir(trgPath))
+
+	linkType, err := CreateLink(srcPath, trgPath, tx.linkTypes)
+
+	// the directories are created before linking, so they need to be recorded even if linking failed
+	for _, dir := range dirs {
+		if _, statErr := os.Stat(dir); statErr == nil {
+			tx.dirs = append(tx.dirs, dir)
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	tx.links = append(tx.links, trgPath)
+
+	return linkType, nil
+}
+
+// Links returns the number of links created by the transaction.
+func (tx *LinkTransaction) Links() int {
+	return len(tx.links)
+}
+
+// Rollback removes all links and directories created by the transaction in reverse order. Directories
+// are only removed if they are empty, so files that didn't get created by the transaction are kept.
+func (tx *LinkTransaction) Rollback() error {
+	errs := make([]string, 0)
+
+	for i := len(tx.links) - 1; i >= 0; i-- {
+		if err := os.Remove(tx.links[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for i := len(tx.dirs) - 1; i >= 0; i-- {
+		if err := os.Remove(tx.dirs[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	tx.links = nil
+	tx.dirs = nil
+
+	if len(errs) > 0 {
+		return errors.New("failed to roll back links: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// missingDirs returns dir and its parents that don't exist yet, starting with the topmost one.
+func missingDirs(dir string) []string {
+	dirs := make([]string, 0)
+
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+
+		dirs = append([]string{dir}, dirs...)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return dirs
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuxencs/seasonpackarr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		linkTypes []string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "default",
+			linkTypes: nil,
+			want:      domain.LinkTypeHardlink,
+		},
+		{
+			name:      "hardlink",
+			linkTypes: []string{domain.LinkTypeHardlink},
+			want:      domain.LinkTypeHardlink,
+		},
+		{
+			name:      "symlink",
+			linkTypes: []string{domain.LinkTypeSymlink},
+			want:      domain.LinkTypeSymlink,
+		},
+		{
+			name:      "copy",
+			linkTypes: []string{domain.LinkTypeCopy},
+			want:      domain.LinkTypeCopy,
+		},
+		{
+			name:      "fallback",
+			linkTypes: []string{"unknown", domain.LinkTypeCopy},
+			want:      domain.LinkTypeCopy,
+		},
+		{
+			name:      "all_failed",
+			linkTypes: []string{"unknown"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			srcPath := filepath.Join(dir, "Series.Title.S01E01.1080p.WEB-DL.H.264-RlsGrp.mkv")
+			trgPath := filepath.Join(dir, "pre-import", "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp", filepath.Base(srcPath))
+			require.NoError(t, os.WriteFile(srcPath, []byte("episode"), 0644))
+
+			got, err := CreateLink(srcPath, trgPath, tt.linkTypes)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.NoFileExists(t, trgPath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			content, err := os.ReadFile(trgPath)
+			require.NoError(t, err)
+			assert.Equal(t, "episode", string(content))
+
+			srcInfo, err := os.Stat(srcPath)
+			require.NoError(t, err)
+			trgInfo, err := os.Lstat(trgPath)
+			require.NoError(t, err)
+
+			switch got {
+			case domain.LinkTypeHardlink:
+				assert.True(t, os.SameFile(srcInfo, trgInfo))
+			case domain.LinkTypeSymlink:
+				assert.Equal(t, os.ModeSymlink, trgInfo.Mode()&os.ModeSymlink)
+			case domain.LinkTypeCopy:
+				assert.False(t, os.SameFile(srcInfo, trgInfo))
+			}
+		})
+	}
+}
+
+func Test_CreateLink_TargetExists(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "src.mkv")
+	trgPath := filepath.Join(dir, "trg.mkv")
+	require.NoError(t, os.WriteFile(srcPath, []byte("episode"), 0644))
+	require.NoError(t, os.WriteFile(trgPath, []byte("existing"), 0644))
+
+	// an existing target isn't overwritten by any of the fallbacks
+	_, err := CreateLink(srcPath, trgPath, []string{domain.LinkTypeHardlink, domain.LinkTypeCopy})
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	content, err := os.ReadFile(trgPath)
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(content))
+}
+
+func Test_LinkTransaction(t *testing.T) {
+	dir := t.TempDir()
+	srcDir := filepath.Join(dir, "torrents")
+	packDir := filepath.Join(dir, "pre-import", "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(packDir), 0755))
+
+	tx := NewLinkTransaction([]string{domain.LinkTypeHardlink})
+
+	for _, name := range []string{"S01E01.mkv", "S01E02.mkv"} {
+		srcPath := filepath.Join(srcDir, name)
+		require.NoError(t, os.WriteFile(srcPath, []byte("episode"), 0644))
+
+		_, err := tx.Link(srcPath, filepath.Join(packDir, "Subs", name))
+		require.NoError(t, err)
+	}
+
+	// linking a missing episode fails, but doesn't touch the existing links
+	_, err := tx.Link(filepath.Join(srcDir, "S01E03.mkv"), filepath.Join(packDir, "Subs", "S01E03.mkv"))
+	assert.Error(t, err)
+	assert.Equal(t, 2, tx.Links())
+	assert.FileExists(t, filepath.Join(packDir, "Subs", "S01E01.mkv"))
+
+	require.NoError(t, tx.Rollback())
+	assert.NoDirExists(t, packDir)
+	assert.DirExists(t, filepath.Join(dir, "pre-import"))
+	assert.FileExists(t, filepath.Join(srcDir, "S01E01.mkv"))
+	assert.Equal(t, 0, tx.Links())
+}
+
+func Test_LinkTransaction_KeepsForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "S01E01.mkv")
+	packDir := filepath.Join(dir, "Series.Title.S01.1080p.WEB-DL.H.264-RlsGrp")
+	require.NoError(t, os.WriteFile(srcPath, []byte("episode"), 0644))
+
+	tx := NewLinkTransaction([]string{domain.LinkTypeHardlink})
+
+	_, err := tx.Link(srcPath, filepath.Join(packDir, "S01E01.mkv"))
+	require.NoError(t, err)
+
+	// a file that wasn't created by the transaction keeps the directory alive
+	require.NoError(t, os.WriteFile(filepath.Join(packDir, "other.nfo"), []byte("nfo"), 0644))
+
+	assert.Error(t, tx.Rollback())
+	assert.NoFileExists(t, filepath.Join(packDir, "S01E01.mkv"))
+	assert.FileExists(t, filepath.Join(packDir, "other.nfo"))
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build linux
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflink clones srcPath to trgPath with the FICLONE ioctl, which is supported by e.g. Btrfs and XFS.
+// Both paths need to be on the same filesystem.
+func reflink(srcPath, trgPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	trg, err := os.OpenFile(trgPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if err = unix.IoctlFileClone(int(trg.Fd()), int(src.Fd())); err != nil {
+		trg.Close()
+		os.Remove(trgPath)
+		return &os.LinkError{Op: "reflink", Old: srcPath, New: trgPath, Err: err}
+	}
+
+	return trg.Close()
+}
@@ -0,0 +1,10 @@
+// Copyright (c) 2023 - 2024, nuxen and the seasonpackarr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+//go:build !linux
+
+package utils
+
+func reflink(_, _ string) error {
+	return ErrReflinkUnsupported
+}
@@ -4,35 +4,124 @@
 package utils
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/nuxencs/seasonpackarr/pkg/errors"
 
 	"github.com/mrobinsn/go-tvmaze/tvmaze"
 )
 
-func GetEpisodesPerSeason(title string, season int) (int, error) {
-	totalEpisodes := 0
+// GetEpisodesPerSeasons returns the number of episodes of every season of the show, keyed by season.
+func GetEpisodesPerSeasons(title string) (map[int]int, error) {
+	show, err := tvmaze.DefaultClient.GetShow(normalizeTitle(title))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find show on tvmaze")
+	}
+
+	episodes, err := show.GetEpisodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get episodes from tvmaze")
+	}
+
+	episodesPerSeason := make(map[int]int)
+	for _, episode := range episodes {
+		episodesPerSeason[episode.Season]++
+	}
+
+	return episodesPerSeason, nil
+}
+
+// SeasonEpisode is the season and episode number of an episode.
+type SeasonEpisode struct {
+	Season  int
+	Episode int
+}
 
+// EpisodeNumbers maps the alternative numberings some releases use to the season and episode number of
+// an episode.
+type EpisodeNumbers struct {
+	// Absolute contains the absolute episode numbers used by most anime releases.
+	Absolute map[int]SeasonEpisode
+	// AirDate contains the air dates used by daily shows, formatted as time.DateOnly in the timezone of the
+	// network the show airs on.
+	AirDate map[string]SeasonEpisode
+}
+
+// GetEpisodeNumbers returns the alternative episode numberings of the show.
+func GetEpisodeNumbers(title string) (*EpisodeNumbers, error) {
 	show, err := tvmaze.DefaultClient.GetShow(normalizeTitle(title))
 	if err != nil {
-		return 0, errors.Wrap(err, "failed to find show on tvmaze")
+		return nil, errors.Wrap(err, "failed to find show on tvmaze")
 	}
 
 	episodes, err := show.GetEpisodes()
 	if err != nil {
-		return 0, errors.Wrap(err, "failed to get episodes from tvmaze")
+		return nil, errors.Wrap(err, "failed to get episodes from tvmaze")
+	}
+
+	absolute := absoluteEpisodes(episodes)
+	if len(absolute) == 0 {
+		return nil, fmt.Errorf("failed to find episodes of %q", title)
 	}
 
+	loc := time.UTC
+	if tz := show.Network.Country.Timezone; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+
+	return &EpisodeNumbers{
+		Absolute: absolute,
+		AirDate:  airDateEpisodes(episodes, loc),
+	}, nil
+}
+
+// airDateEpisodes returns the regular episodes keyed by their air date in loc. If multiple episodes aired on
+// the same day, the first one is used.
+func airDateEpisodes(episodes []tvmaze.Episode, loc *time.Location) map[string]SeasonEpisode {
+	airDate := make(map[string]SeasonEpisode, len(episodes))
+
 	for _, episode := range episodes {
-		if episode.Season == season {
-			totalEpisodes++
+		if episode.Season <= 0 || episode.Number <= 0 || episode.AirDate == nil {
+			continue
+		}
+
+		date := episode.AirDate.In(loc).Format(time.DateOnly)
+		if cur, ok := airDate[date]; ok && (cur.Season < episode.Season ||
+			(cur.Season == episode.Season && cur.Episode < episode.Number)) {
+			continue
 		}
+
+		airDate[date] = SeasonEpisode{Season: episode.Season, Episode: episode.Number}
 	}
 
-	if totalEpisodes == 0 {
-		return 0, fmt.Errorf("failed to find episodes in season %d of %q", season, title)
+	return airDate
+}
+
+// absoluteEpisodes numbers the regular episodes in airing order, specials don't get an absolute number.
+func absoluteEpisodes(episodes []tvmaze.Episode) map[int]SeasonEpisode {
+	regular := make([]SeasonEpisode, 0, len(episodes))
+	for _, episode := range episodes {
+		if episode.Season > 0 && episode.Number > 0 {
+			regular = append(regular, SeasonEpisode{Season: episode.Season, Episode: episode.Number})
+		}
+	}
+
+	slices.SortFunc(regular, func(a, b SeasonEpisode) int {
+		if c := cmp.Compare(a.Season, b.Season); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Episode, b.Episode)
+	})
+
+	absolute := make(map[int]SeasonEpisode, len(regular))
+	for i, episode := range regular {
+		absolute[i+1] = episode
 	}
 
-	return totalEpisodes, nil
+	return absolute
 }
@@ -5,11 +5,13 @@ package utils
 
 import (
 	"testing"
+	"time"
 
+	"github.com/mrobinsn/go-tvmaze/tvmaze"
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_GetEpisodesPerSeason(t *testing.T) {
+func Test_GetEpisodesPerSeasons(t *testing.T) {
 	tests := []struct {
 		name    string
 		title   string
@@ -36,7 +38,7 @@ func Test_GetEpisodesPerSeason(t *testing.T) {
 			title:   "Halo",
 			season:  0,
 			want:    0,
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "show_doesnt_exist",
@@ -62,13 +64,58 @@ func Test_GetEpisodesPerSeason(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := GetEpisodesPerSeason(tt.title, tt.season)
+			got, err := GetEpisodesPerSeasons(tt.title)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			assert.Equalf(t, tt.want, got, "GetEpisodesPerSeason(%s, %d)", tt.title, tt.season)
+			assert.Equalf(t, tt.want, got[tt.season], "GetEpisodesPerSeasons(%s)[%d]", tt.title, tt.season)
 		})
 	}
 }
+
+func Test_absoluteEpisodes(t *testing.T) {
+	episodes := []tvmaze.Episode{
+		{Season: 2, Number: 1},
+		{Season: 1, Number: 2},
+		{Season: 1, Number: 1},
+		{Season: 1, Number: 0},
+		{Season: 0, Number: 1},
+		{Season: 2, Number: 2},
+	}
+
+	assert.Equal(t, map[int]SeasonEpisode{
+		1: {Season: 1, Episode: 1},
+		2: {Season: 1, Episode: 2},
+		3: {Season: 2, Episode: 1},
+		4: {Season: 2, Episode: 2},
+	}, absoluteEpisodes(episodes))
+}
+
+func Test_airDateEpisodes(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	airstamp := func(s string) *time.Time {
+		ts, _ := time.Parse(time.RFC3339, s)
+		return &ts
+	}
+
+	episodes := []tvmaze.Episode{
+		// airs late in the evening in New York, which is already the next day in UTC
+		{Season: 2024, Number: 2, AirDate: airstamp("2024-10-18T03:35:00Z")},
+		{Season: 2024, Number: 1, AirDate: airstamp("2024-10-17T03:35:00Z")},
+		{Season: 2024, Number: 3, AirDate: airstamp("2024-10-18T23:00:00Z")},
+		{Season: 2024, Number: 4, AirDate: nil},
+		{Season: 0, Number: 1, AirDate: airstamp("2024-10-20T03:35:00Z")},
+	}
+
+	assert.Equal(t, map[string]SeasonEpisode{
+		"2024-10-16": {Season: 2024, Episode: 1},
+		"2024-10-17": {Season: 2024, Episode: 2},
+		"2024-10-18": {Season: 2024, Episode: 3},
+	}, airDateEpisodes(episodes, loc))
+}